@@ -1,8 +1,10 @@
 package interactive
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/koinos/go-prompt"
@@ -22,11 +24,14 @@ type KoinosPrompt struct {
 
 	latestRevision int
 
-	onlineDisplay  string
-	offlineDisplay string
-	openDisplay    string
-	closeDisplay   string
-	sessionDisplay string
+	onlineDisplay      string
+	degradedDisplay    string
+	staleDisplay       string
+	unreachableDisplay string
+	offlineDisplay     string
+	openDisplay        string
+	closeDisplay       string
+	sessionDisplay     string
 }
 
 // NewKoinosPrompt creates a new interactive prompt object
@@ -42,12 +47,18 @@ func NewKoinosPrompt(parser *cli.CommandParser, execEnv *cli.ExecutionEnvironmen
 	// Setup status characters
 	if kp.unicodeSupport {
 		kp.onlineDisplay = ""
+		kp.degradedDisplay = "⚠️ "
+		kp.staleDisplay = "⏳ "
+		kp.unreachableDisplay = "📡 "
 		kp.offlineDisplay = "🚫 "
 		kp.closeDisplay = "🔐 "
 		kp.openDisplay = "🔓 "
 		kp.sessionDisplay = "📄 "
 	} else {
 		kp.onlineDisplay = "(online) "
+		kp.degradedDisplay = "(degraded) "
+		kp.staleDisplay = "(stale) "
+		kp.unreachableDisplay = "(unreachable) "
 		kp.offlineDisplay = "(offline) "
 		kp.closeDisplay = "(locked) "
 		kp.openDisplay = "(unlocked) "
@@ -72,10 +83,19 @@ func (kp *KoinosPrompt) generateSuggestions() {
 }
 
 func (kp *KoinosPrompt) changeLivePrefix() (string, bool) {
-	// Calculate online status
+	// Calculate online status from the last heartbeat
 	onlineStatus := kp.offlineDisplay
-	if kp.execEnv.IsOnline() {
-		onlineStatus = kp.onlineDisplay
+	if kp.execEnv.IsConnected() {
+		switch kp.execEnv.NodeHealth().Status {
+		case cliutil.NodeDegraded:
+			onlineStatus = kp.degradedDisplay
+		case cliutil.NodeStale:
+			onlineStatus = kp.staleDisplay
+		case cliutil.NodeUnreachable:
+			onlineStatus = kp.unreachableDisplay
+		default:
+			onlineStatus = kp.onlineDisplay
+		}
 	}
 
 	// Calculate wallet status
@@ -89,7 +109,12 @@ func (kp *KoinosPrompt) changeLivePrefix() (string, bool) {
 		sessionStatus = kp.sessionDisplay
 	}
 
-	return fmt.Sprintf("%s%s%s> ", onlineStatus, walletStatus, sessionStatus), true
+	networkStatus := ""
+	if kp.execEnv.Network != nil {
+		networkStatus = fmt.Sprintf("[%s] ", kp.execEnv.Network.Name)
+	}
+
+	return fmt.Sprintf("%s%s%s%s> ", networkStatus, onlineStatus, walletStatus, sessionStatus), true
 }
 
 func (kp *KoinosPrompt) completer(d prompt.Document) []prompt.Suggest {
@@ -114,7 +139,23 @@ func (kp *KoinosPrompt) completer(d prompt.Document) []prompt.Suggest {
 }
 
 func (kp *KoinosPrompt) executor(input string) {
-	results := cli.ParseAndInterpret(kp.parser, kp.execEnv, input)
+	// Ctrl-C cancels the running command rather than exiting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	results := cli.ParseAndInterpret(ctx, kp.parser, kp.execEnv, input)
 	results.Print()
 }
 
@@ -122,5 +163,9 @@ func (kp *KoinosPrompt) executor(input string) {
 func (kp *KoinosPrompt) Run() {
 	fmt.Printf("Koinos CLI %s\n", cliutil.Version)
 	fmt.Println("Type \"list\" for a list of commands, \"help <command>\" for help on a specific command.")
+
+	// Keep the prompt's online status current while waiting for input
+	kp.execEnv.StartHeartbeat(cliutil.HeartbeatInterval)
+
 	kp.gPrompt.Run()
 }
@@ -1,10 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
-	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/koinos/koinos-cli/cmd/cli/interactive"
@@ -17,21 +17,28 @@ import (
 // Commpand line parameter names
 const (
 	rpcOption              = "rpc"
+	rpcConfigOption        = "rpc-config"
+	rpcHeaderOption        = "rpc-header"
+	rpcProxyOption         = "rpc-proxy"
+	networkOption          = "network"
+	timeoutOption          = "timeout"
+	retriesOption          = "retries"
+	traceRPCOption         = "trace-rpc"
+	traceRPCFileOption     = "trace-rpc-file"
+	traceRPCDecodeOption   = "trace-rpc-decode"
 	executeOption          = "execute"
 	fileOption             = "file"
+	outputOption           = "output"
+	failFastOption         = "fail-fast"
 	versionOption          = "version"
 	forceInteractiveOption = "force-interactive"
 	forceTextPromptOption  = "force-text-prompt"
 )
 
-// Default options
-const (
-	rpcDefault = ""
-)
-
 // Other constants
 const (
-	rcFileName = ".koinosrc"
+	rcFileName             = ".koinosrc"
+	resourceLimitsFileName = ".koinos_resource_limits.json"
 )
 
 func main() {
@@ -39,9 +46,20 @@ func main() {
 	_ = godotenv.Load()
 
 	// Setup command line options
-	rpcAddress := flag.StringP(rpcOption, "r", rpcDefault, "RPC server URL")
+	rpcAddresses := flag.StringSliceP(rpcOption, "r", nil, "RPC server URL. Give several (comma separated or repeated) to fail over between them")
+	rpcConfig := flag.String(rpcConfigOption, "", "RPC config file setting the headers, proxy and TLS of each endpoint")
+	rpcHeaders := flag.StringArray(rpcHeaderOption, nil, "Header sent to the --rpc endpoints, as 'Name: value'. Environment variables in the value are expanded")
+	rpcProxy := flag.String(rpcProxyOption, "", "HTTP(S) or SOCKS5 proxy for the --rpc endpoints")
+	network := flag.StringP(networkOption, "n", "", "Network profile to use (mainnet, testnet, local). Pins the chain id and registers the network's tokens")
+	timeout := flag.Duration(timeoutOption, cliutil.DefaultRPCTimeout, "Timeout for each RPC call")
+	retries := flag.Int(retriesOption, cliutil.DefaultRPCRetries, "Number of times RPC reads are retried when no endpoint can be reached")
+	traceRPC := flag.Bool(traceRPCOption, false, "Log every RPC request and response")
+	traceRPCFile := flag.String(traceRPCFileOption, "", "Write the RPC trace to this file instead of stderr. Implies --trace-rpc")
+	traceRPCDecode := flag.Bool(traceRPCDecodeOption, false, "Show bytes in the RPC trace as hex or base58 instead of base64. Implies --trace-rpc")
 	executeCmd := flag.StringSliceP(executeOption, "x", nil, "Command to execute")
 	fileCmd := flag.StringSliceP(fileOption, "f", nil, "File to execute")
+	output := flag.StringP(outputOption, "o", cli.TextOutput, "Output format, either text or json. json prints one object per command")
+	failFast := flag.Bool(failFastOption, false, "Stop executing commands and files at the first failed command")
 	versionCmd := flag.BoolP(versionOption, "v", false, "Display the version")
 	forceInteractive := flag.BoolP(forceInteractiveOption, "i", false, "Forces interactive mode. Useful for forcing a prompt when using the excute option")
 	forceTextPrompt := flag.BoolP(forceTextPromptOption, "t", false, "Forces text prompt in interactive mode, rather than unicode symbols")
@@ -54,9 +72,40 @@ func main() {
 	}
 
 	// Setup client
+	configs := make([]cliutil.EndpointConfig, 0)
+	if *rpcConfig != "" {
+		config, err := cliutil.LoadRPCConfig(*rpcConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		configs = append(configs, config.Endpoints...)
+	}
+
+	headers := make(map[string]string)
+	for _, header := range *rpcHeaders {
+		name, value, err := cliutil.ParseHeader(header)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		headers[name] = value
+	}
+
+	for _, address := range *rpcAddresses {
+		for _, url := range cliutil.ParseEndpoints(address) {
+			configs = append(configs, cliutil.EndpointConfig{URL: url, Headers: headers, Proxy: *rpcProxy})
+		}
+	}
+
 	var client *cliutil.KoinosRPCClient
-	if *rpcAddress != "" {
-		client = cliutil.NewKoinosRPCClient(*rpcAddress)
+	if len(configs) > 0 {
+		var err error
+		client, err = cliutil.NewKoinosRPCClientWithConfig(configs...)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 	}
 
 	// Construct the command parser
@@ -64,12 +113,68 @@ func main() {
 	parser := cli.NewCommandParser(commands)
 
 	cmdEnv := cli.NewExecutionEnvironment(client, parser)
+	cmdEnv.ResourceLimitsFile = path.Join(util.GetHomeDir(), resourceLimitsFileName)
+
+	if err := cmdEnv.SetOutputFormat(*output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	cmdEnv.FailFast = *failFast
+
+	// Non-interactive runs exit non-zero when any command failed
+	failed := false
+
+	options := cmdEnv.RPCOptions
+	options.Timeout = *timeout
+	options.Retries = *retries
+	cmdEnv.SetRPCOptions(options)
+
+	if *traceRPCFile != "" {
+		tracer, err := cliutil.NewRPCFileTracer(*traceRPCFile, *traceRPCDecode)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		cmdEnv.SetRPCTracer(tracer)
+	} else if *traceRPC || *traceRPCDecode {
+		cmdEnv.SetRPCTracer(cliutil.NewRPCTracer(os.Stderr, *traceRPCDecode))
+	}
+
+	if *network != "" {
+		if len(configs) > 0 {
+			fmt.Fprintln(os.Stderr, "--network cannot be combined with --rpc or --rpc-config")
+			os.Exit(1)
+		}
+
+		profile, ok := cmdEnv.Networks[*network]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown network %s\n", *network)
+			os.Exit(1)
+		}
+
+		result, err := cli.UseNetwork(context.Background(), cmdEnv, profile)
+		ir := cli.NewInterpretResults()
+		ir.Format = cmdEnv.OutputFormat
+		ir.AddCommandResult(context.Background(), networkOption, result, err)
+		ir.Print()
+		if err != nil {
+			os.Exit(1)
+		}
+	}
 
 	// If the user submitted commands, execute them
 	if *executeCmd != nil {
 		for _, cmd := range *executeCmd {
-			results := cli.ParseAndInterpret(parser, cmdEnv, cmd)
+			results := cli.ParseAndInterpret(context.Background(), parser, cmdEnv, cmd)
 			results.Print()
+
+			if results.Failed() {
+				failed = true
+				if results.Halted {
+					os.Exit(1)
+				}
+			}
 		}
 	}
 
@@ -86,26 +191,19 @@ func main() {
 			continue
 		}
 
-		data, err := os.ReadFile(file)
+		results, err := cli.InterpretFile(context.Background(), cmdEnv, file)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
-		results := make([]string, 0)
+		results.Print()
 
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			ir := cli.ParseAndInterpret(parser, cmdEnv, line)
-			results = append(results, ir.Results...)
-		}
-
-		for _, result := range results {
-			fmt.Println(result)
-		}
-
-		if len(results) > 0 {
-			fmt.Println()
+		if results.Failed() {
+			failed = true
+			if results.Halted {
+				os.Exit(1)
+			}
 		}
 	}
 
@@ -115,4 +213,8 @@ func main() {
 		p := interactive.NewKoinosPrompt(parser, cmdEnv, *forceTextPrompt)
 		p.Run()
 	}
+
+	if failed {
+		os.Exit(1)
+	}
 }
@@ -12,6 +12,7 @@ require (
 	github.com/koinos/koinos-util-golang/v2 v2.0.1
 	github.com/minio/sio v0.3.0
 	github.com/multiformats/go-multihash v0.1.0
+	github.com/rabbitmq/amqp091-go v1.9.0
 	github.com/shopspring/decimal v1.3.1
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.8.1
@@ -9,8 +9,10 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/koinos/koinos-cli/internal/cliutil"
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
 	"github.com/koinos/koinos-proto-golang/v2/koinos"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/chain"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/contracts/token"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	util "github.com/koinos/koinos-util-golang/v2"
 	"google.golang.org/protobuf/proto"
@@ -25,6 +27,7 @@ import (
 // ABI is the ABI of the contract
 type ABI struct {
 	Methods map[string]*ABIMethod
+	Events  map[string]*ABIEvent
 	Types   []byte
 }
 
@@ -116,12 +119,24 @@ type ABIMethod struct {
 	ReadOnly    bool   `json:"read-only"`
 }
 
+// ABIEvent represents an ABI event descriptor
+type ABIEvent struct {
+	Argument string `json:"argument"`
+}
+
+// TokenInfo is the symbol and precision of a registered token
+type TokenInfo struct {
+	Symbol    string
+	Precision int
+}
+
 // ContractInfo represents the information about a contract
 type ContractInfo struct {
 	Name     string
 	Address  string // []byte?
 	ABI      *ABI
 	Registry *protoregistry.Files
+	Token    *TokenInfo // Set for contracts registered with register_token
 }
 
 // Contracts is a map of contract names to ContractInfo
@@ -205,6 +220,119 @@ func (c Contracts) getMethodData(methodName string, getArguments bool) (protoref
 	return md, nil
 }
 
+// GetFromAddress returns contract info from a contract address
+func (c Contracts) GetFromAddress(address string) *ContractInfo {
+	for _, contract := range c {
+		if contract.Address == address {
+			return contract
+		}
+	}
+
+	return nil
+}
+
+// ContractName returns the registered name of the contract at the given address
+func (c Contracts) ContractName(address []byte) (string, bool) {
+	contract := c.GetFromAddress(base58.Encode(address))
+	if contract == nil {
+		return "", false
+	}
+
+	return contract.Name, true
+}
+
+// DecodeEvent decodes event data using the ABI of the contract that emitted it,
+// falling back to the globally known koinos types
+func (c Contracts) DecodeEvent(event *protocol.EventData) (string, error) {
+	var md protoreflect.MessageDescriptor
+
+	contract := c.GetFromAddress(base58.Encode(event.Source))
+	if contract != nil && contract.Registry != nil {
+		typeName := event.Name
+		if contract.ABI != nil {
+			if abiEvent, ok := contract.ABI.Events[event.Name]; ok && len(abiEvent.Argument) > 0 {
+				typeName = abiEvent.Argument
+			}
+		}
+
+		if d, err := contract.Registry.FindDescriptorByName(protoreflect.FullName(typeName)); err == nil {
+			md, _ = d.(protoreflect.MessageDescriptor)
+		}
+	}
+
+	if md == nil {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(event.Name))
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", cliutil.ErrUnknownEventType, event.Name)
+		}
+
+		md = mt.Descriptor()
+	}
+
+	return decodeMessageJSON(md, event.Data)
+}
+
+// DecodeCall decodes the arguments of a contract call using the ABI of the registered contract
+func (c Contracts) DecodeCall(contractID []byte, entryPoint uint32, args []byte) (string, string, error) {
+	contract := c.GetFromAddress(base58.Encode(contractID))
+	if contract == nil {
+		return "", "", fmt.Errorf("%w: contract %s is not registered", cliutil.ErrContract, base58.Encode(contractID))
+	}
+
+	var methodName string
+	var md protoreflect.MessageDescriptor
+
+	if contract.ABI != nil {
+		for name, method := range contract.ABI.Methods {
+			if len(method.EntryPoint) < 3 {
+				continue
+			}
+
+			ep, err := strconv.ParseUint(method.EntryPoint[2:], 16, 32)
+			if err != nil || uint32(ep) != entryPoint {
+				continue
+			}
+
+			methodName = contract.Name + "." + name
+			md, err = c.GetMethodArguments(methodName)
+			if err != nil {
+				return "", "", err
+			}
+
+			break
+		}
+	} else if entryPoint == TokenTransferEntry { // Tokens registered with register_token have no ABI
+		methodName = contract.Name + ".transfer"
+		md = (&token.TransferArguments{}).ProtoReflect().Descriptor()
+	}
+
+	if md == nil {
+		return "", "", fmt.Errorf("%w: unknown entry point 0x%08x for contract %s", cliutil.ErrContract, entryPoint, contract.Name)
+	}
+
+	decoded, err := decodeMessageJSON(md, args)
+	if err != nil {
+		return "", "", err
+	}
+
+	return methodName, decoded, nil
+}
+
+func decodeMessageJSON(md protoreflect.MessageDescriptor, data []byte) (string, error) {
+	msg := dynamicpb.NewMessage(md)
+	err := proto.Unmarshal(data, msg)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := kjson.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 // Contains returns true if the contract exists
 func (c Contracts) Contains(name string) bool {
 	_, ok := c[name]
@@ -1,10 +1,18 @@
 package cli
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/contracts/token"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -80,3 +88,84 @@ func TestABI(t *testing.T) {
 	testMethod(t, contracts, "abi_test.nested", []string{"name", "data.name", "data.a.value", "data.a.name", "data.a.num",
 		"data.value", "data.b.active", "data.b.name", "value"})
 }
+
+func TestDecodeEvent(t *testing.T) {
+	contracts := loadContracts(t)
+
+	from := base58.Decode("1GbiqgoMhvkztWytizNPn8g5SvXrrYHQQg")
+	to := base58.Decode("16KsSj5mUG6XhD5DUbJtZmGoXAC7Jkg3nm")
+	eventData, err := proto.Marshal(&token.TransferEvent{From: from, To: to, Value: 100})
+	assert.NoError(t, err)
+
+	event := &protocol.EventData{
+		Sequence: 1,
+		Source:   base58.Decode(cliutil.KoinContractID),
+		Name:     "koinos.contracts.token.transfer_event",
+		Data:     eventData,
+		Impacted: [][]byte{to, from},
+	}
+
+	// Known types are decoded with their address fields in base58
+	decoded, err := contracts.DecodeEvent(event)
+	assert.NoError(t, err)
+	assert.Contains(t, decoded, "1GbiqgoMhvkztWytizNPn8g5SvXrrYHQQg")
+	assert.Contains(t, decoded, "\"value\":\"100\"")
+
+	s := cliutil.EventToString(event, contracts)
+	assert.Contains(t, s, "koinos.contracts.token.transfer_event from "+cliutil.KoinContractID)
+	assert.Contains(t, s, "Impacted: 16KsSj5mUG6XhD5DUbJtZmGoXAC7Jkg3nm, 1GbiqgoMhvkztWytizNPn8g5SvXrrYHQQg")
+
+	// Unknown types fall back to hex
+	event.Name = "unknown_event"
+	_, err = contracts.DecodeEvent(event)
+	assert.ErrorIs(t, err, cliutil.ErrUnknownEventType)
+
+	s = cliutil.EventToString(event, contracts)
+	assert.Contains(t, s, "Data: 0x"+hex.EncodeToString(eventData))
+}
+
+func TestOperationRendering(t *testing.T) {
+	contracts := loadContracts(t)
+	err := contracts.Add("koin", cliutil.KoinContractID, nil, nil)
+	assert.NoError(t, err)
+
+	key, err := util.GenerateKoinosKey()
+	assert.NoError(t, err)
+
+	to := base58.Decode("16KsSj5mUG6XhD5DUbJtZmGoXAC7Jkg3nm")
+	args, err := proto.Marshal(&token.TransferArguments{From: key.AddressBytes(), To: to, Value: 100})
+	assert.NoError(t, err)
+
+	ops := []*protocol.Operation{
+		{
+			Op: &protocol.Operation_CallContract{
+				CallContract: &protocol.CallContractOperation{
+					ContractId: base58.Decode(cliutil.KoinContractID),
+					EntryPoint: TokenTransferEntry,
+					Args:       args,
+				},
+			},
+		},
+		{
+			Op: &protocol.Operation_CallContract{
+				CallContract: &protocol.CallContractOperation{
+					ContractId: to,
+					EntryPoint: 0x01,
+					Args:       []byte{0x0a},
+				},
+			},
+		},
+	}
+
+	transaction, err := cliutil.CreateSignedTransaction(context.Background(), ops, key, 1, 100000000, []byte{0x00}, key.AddressBytes())
+	assert.NoError(t, err)
+
+	signers, err := cliutil.TransactionSigners(transaction)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{base58.Encode(key.AddressBytes())}, signers)
+
+	s := cliutil.TransactionToString(transaction, contracts)
+	assert.Contains(t, s, "Signers: "+base58.Encode(key.AddressBytes()))
+	assert.Contains(t, s, "0: Call koin.transfer on contract koin ("+cliutil.KoinContractID+")")
+	assert.Contains(t, s, "1: Call contract 16KsSj5mUG6XhD5DUbJtZmGoXAC7Jkg3nm at entry point 0x00000001 with arguments 0x0a")
+}
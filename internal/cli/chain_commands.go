@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/block_store"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+const (
+	// MaxBlocksRange is the maximum number of blocks that can be fetched by the blocks command
+	MaxBlocksRange = 1000
+)
+
+// ----------------------------------------------------------------------------
+// HeadInfo Command
+// ----------------------------------------------------------------------------
+
+// HeadInfoCommand is a command that shows the head info of the chain
+type HeadInfoCommand struct {
+}
+
+// NewHeadInfoCommand creates a new head info command object
+func NewHeadInfoCommand(inv *CommandParseResult) Command {
+	return &HeadInfoCommand{}
+}
+
+// Execute shows the head info of the chain
+func (c *HeadInfoCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get head info", cliutil.ErrOffline)
+	}
+
+	headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Head block: %s", util.BlockTopologyString(headInfo.HeadTopology)))
+	result.AddMessage(fmt.Sprintf("Head block time: %s (%s ago)", cliutil.TimestampToString(headInfo.HeadBlockTime), blockAge(headInfo.HeadBlockTime)))
+	result.AddMessage(fmt.Sprintf("Last irreversible block: %d", headInfo.LastIrreversibleBlock))
+	result.AddMessage(fmt.Sprintf("Head state merkle root: 0x%s", hex.EncodeToString(headInfo.HeadStateMerkleRoot)))
+	result.SetData(protoData(headInfo))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Block Command
+// ----------------------------------------------------------------------------
+
+// BlockCommand is a command that shows a block by height or id
+type BlockCommand struct {
+	Block string
+	Ops   *string
+}
+
+// NewBlockCommand creates a new block command object
+func NewBlockCommand(inv *CommandParseResult) Command {
+	return &BlockCommand{Block: *inv.Args["block"], Ops: inv.Args["ops"]}
+}
+
+// Execute shows a block
+func (c *BlockCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get block", cliutil.ErrOffline)
+	}
+
+	showOps := false
+	if c.Ops != nil {
+		var err error
+		showOps, err = strconv.ParseBool(*c.Ops)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var items []*block_store.BlockItem
+	if strings.HasPrefix(c.Block, "0x") {
+		blockID, err := util.HexStringToBytes(c.Block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: block id must be a hex string", cliutil.ErrInvalidParam)
+		}
+
+		items, err = ee.RPCClient.GetBlocksByID(ctx, [][]byte{blockID}, true)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		height, err := strconv.ParseUint(c.Block, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: block must be either a height or a hex block id", cliutil.ErrInvalidParam)
+		}
+
+		headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err = ee.RPCClient.GetBlocksByHeight(ctx, headInfo.HeadTopology.Id, height, 1, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(items) == 0 || items[0].Block == nil {
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrBlockNotFound, c.Block)
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(cliutil.BlockToString(items[0].Block, items[0].Receipt, showOps, ee.Contracts))
+	result.SetData(protoData(items[0]))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Blocks Command
+// ----------------------------------------------------------------------------
+
+// BlocksCommand is a command that lists a range of blocks
+type BlocksCommand struct {
+	From string
+	To   string
+}
+
+// NewBlocksCommand creates a new blocks command object
+func NewBlocksCommand(inv *CommandParseResult) Command {
+	return &BlocksCommand{From: *inv.Args["from"], To: *inv.Args["to"]}
+}
+
+// Execute lists a range of blocks
+func (c *BlocksCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get blocks", cliutil.ErrOffline)
+	}
+
+	from, err := strconv.ParseUint(c.From, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := strconv.ParseUint(c.To, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == 0 || to < from {
+		return nil, fmt.Errorf("%w: block range must start at 1 or higher and end at or after its start", cliutil.ErrInvalidParam)
+	}
+
+	if to-from+1 > MaxBlocksRange {
+		return nil, fmt.Errorf("%w: cannot fetch more than %d blocks at once", cliutil.ErrInvalidParam, MaxBlocksRange)
+	}
+
+	headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := ee.RPCClient.GetBlocksByHeight(ctx, headInfo.HeadTopology.Id, from, uint32(to-from+1), false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	data := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		if item.Block == nil {
+			continue
+		}
+
+		result.AddMessage(cliutil.BlockSummaryString(item.Block))
+		data = append(data, protoData(item.Block))
+	}
+	result.SetData(data)
+
+	if len(items) == 0 {
+		result.AddMessage(fmt.Sprintf("No blocks between heights %d and %d", from, to))
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// ForkHeads Command
+// ----------------------------------------------------------------------------
+
+// ForkHeadsCommand is a command that shows the current fork heads
+type ForkHeadsCommand struct {
+}
+
+// NewForkHeadsCommand creates a new fork heads command object
+func NewForkHeadsCommand(inv *CommandParseResult) Command {
+	return &ForkHeadsCommand{}
+}
+
+// Execute shows the current fork heads
+func (c *ForkHeadsCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get fork heads", cliutil.ErrOffline)
+	}
+
+	forkHeads, err := ee.RPCClient.GetForkHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Last irreversible block: %s", util.BlockTopologyString(forkHeads.LastIrreversibleBlock)))
+	result.AddMessage(fmt.Sprintf("Fork heads (%d):", len(forkHeads.ForkHeads)))
+	for i, head := range forkHeads.ForkHeads {
+		result.AddMessage(fmt.Sprintf("%d: %s", i, util.BlockTopologyString(head)))
+	}
+	result.SetData(protoData(forkHeads))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// ChainStatus Command
+// ----------------------------------------------------------------------------
+
+// ChainStatusCommand is a command that shows a summary of the state of the chain
+type ChainStatusCommand struct {
+}
+
+// NewChainStatusCommand creates a new chain status command object
+func NewChainStatusCommand(inv *CommandParseResult) Command {
+	return &ChainStatusCommand{}
+}
+
+// Execute shows a summary of the state of the chain
+func (c *ChainStatusCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get chain status", cliutil.ErrOffline)
+	}
+
+	chainID, err := ee.RPCClient.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	forkHeads, err := ee.RPCClient.GetForkHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	head := headInfo.HeadTopology
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Chain ID: %s", base64.URLEncoding.EncodeToString(chainID)))
+	result.AddMessage(fmt.Sprintf("Head block: %d (0x%s)", head.Height, hex.EncodeToString(head.Id)))
+	result.AddMessage(fmt.Sprintf("Head block time: %s (%s ago)", cliutil.TimestampToString(headInfo.HeadBlockTime), blockAge(headInfo.HeadBlockTime)))
+	result.AddMessage(fmt.Sprintf("Last irreversible block: %d (%d blocks behind head)", headInfo.LastIrreversibleBlock, head.Height-headInfo.LastIrreversibleBlock))
+	result.AddMessage(fmt.Sprintf("Fork heads: %d", len(forkHeads.ForkHeads)))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// TxInfo Command
+// ----------------------------------------------------------------------------
+
+// TxInfoCommand is a command that shows a transaction and its receipt by id
+type TxInfoCommand struct {
+	ID string
+}
+
+// NewTxInfoCommand creates a new tx info command object
+func NewTxInfoCommand(inv *CommandParseResult) Command {
+	return &TxInfoCommand{ID: *inv.Args["id"]}
+}
+
+// Execute shows a transaction and its receipt
+func (c *TxInfoCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get transaction", cliutil.ErrOffline)
+	}
+
+	transactionID, err := util.HexStringToBytes(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: transaction id must be a hex string", cliutil.ErrInvalidParam)
+	}
+
+	items, err := ee.RPCClient.GetTransactionsByID(ctx, [][]byte{transactionID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 || items[0].Transaction == nil {
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrTransactionNotFound, c.ID)
+	}
+
+	transaction := items[0].Transaction
+	result := NewExecutionResult()
+	result.AddMessage(cliutil.TransactionToString(transaction, ee.Contracts))
+	data := &TransactionData{TransactionID: "0x" + hex.EncodeToString(transaction.GetId()), Transaction: protoData(transaction)}
+	result.SetData(data)
+
+	if len(items[0].ContainingBlocks) == 0 {
+		result.AddMessage("Transaction is not included in any block")
+		return result, nil
+	}
+
+	headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := ee.RPCClient.GetBlocksByID(ctx, items[0].ContainingBlocks, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// A block is only final when it is the block at its height on the main chain. The main chain is read at the height
+	// of each containing block concurrently
+	mainBlocks := make([][]*block_store.BlockItem, len(blocks))
+	errs := cliutil.FanOut(ctx, len(blocks), cliutil.MaxConcurrentCalls, func(ctx context.Context, i int) error {
+		if blocks[i].Block == nil || blocks[i].Block.Header == nil {
+			return nil
+		}
+
+		var err error
+		mainBlocks[i], err = ee.RPCClient.GetBlocksByHeight(ctx, headInfo.HeadTopology.Id, blocks[i].BlockHeight, 1, false)
+		return err
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, block := range blocks {
+		if block.Block == nil || block.Block.Header == nil {
+			continue
+		}
+
+		status := "reversible"
+		switch {
+		case len(mainBlocks[i]) == 0 || !bytes.Equal(mainBlocks[i][0].BlockId, block.BlockId):
+			status = "on a fork, not the main chain"
+		case block.BlockHeight <= headInfo.LastIrreversibleBlock:
+			status = "irreversible"
+		}
+
+		result.AddMessage(fmt.Sprintf("Included in block %d (0x%s) at %s, %s", block.BlockHeight, hex.EncodeToString(block.BlockId), cliutil.TimestampToString(block.Block.Header.Timestamp), status))
+
+		if block.Receipt == nil {
+			continue
+		}
+
+		for _, receipt := range block.Receipt.TransactionReceipts {
+			if bytes.Equal(receipt.Id, transaction.Id) {
+				result.AddMessage(cliutil.TransactionReceiptToString(receipt, len(transaction.Operations), ee.Contracts))
+				data.Receipt = protoData(receipt)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// blockAge returns how long ago the given block timestamp was, rounded to the second
+func blockAge(timestamp uint64) time.Duration {
+	return time.Since(cliutil.TimestampToTime(timestamp)).Round(time.Second)
+}
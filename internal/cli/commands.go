@@ -1,9 +1,9 @@
 package cli
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,15 +11,14 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/koinos/koinos-cli/internal/cliutil"
-	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/chain"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	"github.com/shopspring/decimal"
-	"google.golang.org/protobuf/proto"
 
 	util "github.com/koinos/koinos-util-golang/v2"
 )
@@ -49,6 +48,23 @@ func (cs *CommandSet) AddCommand(decl *CommandDeclaration) {
 	cs.Revision++
 }
 
+// RemoveCommand removes a command from the command set
+func (cs *CommandSet) RemoveCommand(name string) {
+	if _, ok := cs.Name2Command[name]; !ok {
+		return
+	}
+
+	for i, decl := range cs.Commands {
+		if decl.Name == name {
+			cs.Commands = append(cs.Commands[:i], cs.Commands[i+1:]...)
+			break
+		}
+	}
+
+	delete(cs.Name2Command, name)
+	cs.Revision++
+}
+
 // List returns an alphabetized list of commands. The pretty argument makes it return the commands in neat columns with the descriptions
 func (cs *CommandSet) List(pretty bool) []string {
 	names := make([]string, 0)
@@ -94,11 +110,16 @@ func NewKoinosCommandSet() *CommandSet {
 	cs := NewCommandSet()
 
 	cs.AddCommand(NewCommandDeclaration("address", "Show the currently opened wallet's address", false, NewAddressCommand))
-	cs.AddCommand(NewCommandDeclaration("connect", "Connect to an RPC endpoint", false, NewConnectCommand, *NewCommandArg("url", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("connect", "Connect to an RPC endpoint. Give several comma separated urls to fail over between them, or an RPC config file (.json) to set headers, proxies and TLS per endpoint. amqp:// urls call the microservices directly through an AMQP broker", false, NewConnectCommand, *NewCommandArg("url", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("close", "Close the currently open wallet (lock also works)", false, NewCloseCommand))
 	cs.AddCommand(NewCommandDeclaration("lock", "Synonym for close", true, NewCloseCommand))
 	cs.AddCommand(NewCommandDeclaration("create", "Create and open a new wallet file", false, NewCreateCommand, *NewCommandArg("filename", FileArg), *NewOptionalCommandArg("password", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("debug", "Turn debug output on or off. 'debug rpc on' traces RPC calls, 'debug rpc decode' also shows bytes as hex or base58. Give a filename to write the trace to a file", false, NewDebugCommand, *NewCommandArg("subsystem", StringArg), *NewCommandArg("mode", StringArg), *NewOptionalCommandArg("filename", FileArg)))
 	cs.AddCommand(NewCommandDeclaration("disconnect", "Disconnect from RPC endpoint", false, NewDisconnectCommand))
+	cs.AddCommand(NewCommandDeclaration("network", "List network profiles, switch to one with 'network use <name>', or add one with 'network add <name> <url> [chain id]'. A network pins the chain id that transactions are signed for", false, NewNetworkCommand, *NewOptionalCommandArg("action", StringArg), *NewOptionalCommandArg("name", StringArg), *NewOptionalCommandArg("url", StringArg), *NewOptionalCommandArg("chain_id", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("endpoints", "Check and show the status of each connected RPC endpoint", false, NewEndpointsCommand))
+	cs.AddCommand(NewCommandDeclaration("rpc", "Make a raw RPC call with JSON params (e.g. rpc chain.get_account_rc '{\"account\":\"1...\"}'). Params of known methods are validated and bytes in the response are shown as hex or base58", false, NewRPCCommand, *NewCommandArg("method", StringArg), *NewOptionalCommandArg("params", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("rpc_timeout", "Set or show the RPC call timeout in seconds and how many times reads are retried. Blank to view", false, NewRPCTimeoutCommand, *NewOptionalCommandArg("seconds", AmountArg), *NewOptionalCommandArg("retries", UIntArg)))
 	cs.AddCommand(NewCommandDeclaration("generate", "Generate and display a new private key", false, NewGenerateKeyCommand))
 	cs.AddCommand(NewCommandDeclaration("help", "Show help on a given command", false, NewHelpCommand, *NewCommandArg("command", CmdNameArg)))
 	cs.AddCommand(NewCommandDeclaration("import", "Import a WIF private key to a new wallet file", false, NewImportCommand, *NewCommandArg("private-key", StringArg), *NewCommandArg("filename", FileArg), *NewOptionalCommandArg("password", StringArg)))
@@ -108,21 +129,46 @@ func NewKoinosCommandSet() *CommandSet {
 	cs.AddCommand(NewCommandDeclaration("open", "Open a wallet file (unlock also works)", false, NewOpenCommand, *NewCommandArg("filename", FileArg), *NewOptionalCommandArg("password", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("unlock", "Synonym for open", true, NewOpenCommand, *NewCommandArg("filename", FileArg), *NewOptionalCommandArg("password", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("nonce", "Set nonce for transactions. 'auto' will default to querying for nonce. Blank nonce to view", false, NewNonceCommand, *NewOptionalCommandArg("nonce", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("output", "Set the output format, either text or json. json prints one object per command. Blank format to view", false, NewOutputCommand, *NewOptionalCommandArg("format", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("chain_id", "Set chain id in base64 for transactions. 'auto' will default to querying for chain id. Blank id to view", false, NewChainIDCommand, *NewOptionalCommandArg("id", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("payer", "Set the payer address for transactions. 'me' will default to current wallet. Blank address to view", false, NewPayerCommand, *NewOptionalCommandArg("payer", AddressArg)))
 	cs.AddCommand(NewCommandDeclaration("private", "Show the currently opened wallet's private key", false, NewPrivateCommand))
 	cs.AddCommand(NewCommandDeclaration("public", "Show the currently opened wallet's public key", false, NewPublicCommand))
-	cs.AddCommand(NewCommandDeclaration("rclimit", "Set or show the current rc limit. Give no limit to see current value. Give limit as either mana or a percent (i.e. 80%).", false, NewRcLimitCommand, *NewOptionalCommandArg("limit", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("rclimit", "Set or show the current rc limit. Give no limit to see current value. Give limit as either mana or a percent (e.g. 80%).", false, NewRcLimitCommand, *NewOptionalCommandArg("limit", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("multiread", "Run several read-only contract methods in one batch (e.g. multiread 'koin.balance_of 1A...; koin.balance_of 1B...')", false, NewMultiReadCommand, *NewCommandArg("commands", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("read", "Read from a smart contract", false, NewReadCommand, *NewCommandArg("contract-id", StringArg), *NewCommandArg("entry-point", StringArg), *NewCommandArg("arguments", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("register", "Register a smart contract's commands", false, NewRegisterCommand, *NewCommandArg("name", ContractNameArg), *NewCommandArg("address", AddressArg), *NewOptionalCommandArg("abi-filename", FileArg)))
 	cs.AddCommand(NewCommandDeclaration("register_token", "Register a token's commands", false, NewRegisterTokenCommand, *NewCommandArg("name", ContractNameArg), *NewCommandArg("address", AddressArg), *NewOptionalCommandArg("symbol", StringArg), *NewOptionalCommandArg("precision", StringArg)))
-	cs.AddCommand(NewCommandDeclaration("account_rc", "Get the current resource credits for a given address (open wallet if blank)", false, NewAccountRcCommand, *NewOptionalCommandArg("address", AddressArg)))
+	cs.AddCommand(NewCommandDeclaration("account_rc", "Get the current and maximum mana of an address (open wallet if blank), how fast it regenerates, and when a target amount is available", false, NewAccountRcCommand, *NewOptionalCommandArg("address", AddressArg), *NewOptionalCommandArg("target", AmountArg)))
+	cs.AddCommand(NewCommandDeclaration("wait_for_mana", "Wait until an address (open wallet if blank) has regenerated the given amount of mana", false, NewWaitForManaCommand, *NewCommandArg("amount", AmountArg), *NewOptionalCommandArg("address", AddressArg)))
+	cs.AddCommand(NewCommandDeclaration("account_history", "Show the transactions and blocks affecting an address (open wallet if blank), newest first. Give a sequence number to start from it", false, NewAccountHistoryCommand, *NewOptionalCommandArg("address", AddressArg), *NewOptionalCommandArg("limit", UIntArg), *NewOptionalCommandArg("from", UIntArg)))
+	cs.AddCommand(NewCommandDeclaration("account_history_export", "Export the history of an address (open wallet if blank) to a .csv or .json file", false, NewAccountHistoryExportCommand, *NewCommandArg("filename", FileArg), *NewOptionalCommandArg("address", AddressArg), *NewOptionalCommandArg("limit", UIntArg), *NewOptionalCommandArg("from", UIntArg)))
+	cs.AddCommand(NewCommandDeclaration("resource_limits", "Show the chain's resource limits and costs per block (cached ones when offline)", false, NewResourceLimitsCommand))
+	cs.AddCommand(NewCommandDeclaration("estimate_cost", "Estimate the mana of the transaction the commands would submit, without signing it (e.g. estimate_cost 'koin.transfer 1A... 10')", false, NewEstimateCostCommand, *NewCommandArg("commands", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("mempool", "Inspect the mempool. 'pending [address]' lists pending transactions, 'check <tx-id>' finds a transaction, 'nonce [address]' shows the gap between the on-chain and pending nonce of an address (open wallet if blank)", false, NewMempoolCommand, *NewCommandArg("action", StringArg), *NewOptionalCommandArg("target", StringArg)))
 	cs.AddCommand(NewCommandDeclaration("account_nonce", "Get the current nonce for a given address (open wallet if blank)", false, NewAccountNonceCommand, *NewOptionalCommandArg("address", AddressArg)))
+	cs.AddCommand(NewCommandDeclaration("head_info", "Show the head block, head block time and last irreversible block of the chain", false, NewHeadInfoCommand))
+	cs.AddCommand(NewCommandDeclaration("block", "Show a block by height or id. Give --ops to also show each transaction and operation", false, NewBlockCommand, *NewCommandArg("block", StringArg), *NewOptionalCommandArg("ops", BoolArg)))
+	cs.AddCommand(NewCommandDeclaration("blocks", "List the blocks between two heights (inclusive)", false, NewBlocksCommand, *NewCommandArg("from", UIntArg), *NewCommandArg("to", UIntArg)))
+	cs.AddCommand(NewCommandDeclaration("fork_heads", "Show the current fork heads and last irreversible block", false, NewForkHeadsCommand))
+	cs.AddCommand(NewCommandDeclaration("chain_status", "Show a summary of the chain id, head block and forks", false, NewChainStatusCommand))
+	cs.AddCommand(NewCommandDeclaration("tx_info", "Show a transaction, its receipt and its containing block by transaction id", false, NewTxInfoCommand, *NewCommandArg("id", HexArg)))
 	cs.AddCommand(NewCommandDeclaration("set_system_call", "Set a system call to a new contract and entry point", false, NewSetSystemCallCommand, *NewCommandArg("system-call", StringArg), *NewCommandArg("contract-id", AddressArg), *NewCommandArg("entry-point", HexArg)))
 	cs.AddCommand(NewCommandDeclaration("set_system_contract", "Change a contract's permission level between user and system", false, NewSetSystemContractCommand, *NewCommandArg("contract-id", AddressArg), *NewCommandArg("system-contract", BoolArg)))
 	cs.AddCommand(NewCommandDeclaration("session", "Create or manage a transaction session (begin, submit, cancel, or view)", false, NewSessionCommand, *NewCommandArg("command", StringArg)))
-	cs.AddCommand(NewCommandDeclaration("sign_transaction", "Signs a transaction with the open wallet, adding it to the transaction", true, NewSignTransactionCommand, *NewCommandArg("transaction", StringArg)))
-	cs.AddCommand(NewCommandDeclaration("submit_transaction", "Submit a transaction from base64 data", false, NewSubmitTransactionCommand, *NewCommandArg("transaction", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("sign_transaction", "Signs a transaction (JSON or base64, inline or from a file) with the open wallet. Format is one of (json, base64, both)", true, NewSignTransactionCommand, *NewCommandArg("transaction", StringArg), *NewOptionalCommandArg("format", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("submit_transaction", "Submit a transaction from JSON or base64 data, inline or from a file", false, NewSubmitTransactionCommand, *NewCommandArg("transaction", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("set", "Set a variable, which later commands refer to as $name or ${name}. Give no value to show a variable, or no name to list them", false, NewSetCommand, *NewOptionalCommandArg("name", StringArg), *NewOptionalCommandArg("value", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("unset", "Remove a variable", false, NewUnsetCommand, *NewCommandArg("name", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("let", "Run a command and set a variable to its result, e.g. let id = koin.transfer 1A... 10. Fields of the result are set as $name.field", false, NewLetCommand, *NewCommandArg("name", StringArg), *NewCommandArg("command", CommandLineArg)))
+	cs.AddCommand(NewCommandDeclaration("if", "Run commands if a condition holds, otherwise the else commands, e.g. if '$balance > 10' 'koin.transfer 1A... 10' else 'koin.transfer 1A... 1'", false, NewIfCommand, *NewCommandArg("condition", StringArg), *NewCommandArg("commands", StringArg), *NewOptionalCommandArg("else", StringArg), *NewOptionalCommandArg("else-commands", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("for", "Run commands with a variable set to each value of a list or range, e.g. for addr in '1A... 1B...' 'koin.balance_of $addr'. A range such as 1..10 includes both ends", false, NewForCommand, *NewCommandArg("name", StringArg), *NewCommandArg("in", StringArg), *NewCommandArg("list", StringArg), *NewCommandArg("commands", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("repeat", "Run commands a number of times, e.g. repeat 3 'koin.transfer 1A... 1'", false, NewRepeatCommand, *NewCommandArg("count", UIntArg), *NewCommandArg("commands", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("source", "Run the commands in a script file", false, NewSourceCommand, *NewCommandArg("filename", FileArg)))
+	cs.AddCommand(NewCommandDeclaration("assert", "Fail and stop the script unless a condition holds, e.g. assert '$nonce == 2' 'nonce not updated'", false, NewAssertCommand, *NewCommandArg("condition", StringArg), *NewOptionalCommandArg("message", StringArg)))
+	cs.AddCommand(NewCommandDeclaration("expect_revert", "Fail and stop the script unless the command is reverted, e.g. expect_revert koin.transfer 1A... 1000000", false, NewExpectRevertCommand, *NewCommandArg("command", CommandLineArg)))
+	cs.AddCommand(NewCommandDeclaration("expect_balance", "Fail and stop the script unless an address has the given balance of a registered token", false, NewExpectBalanceCommand, *NewCommandArg("token", ContractNameArg), *NewCommandArg("address", AddressArg), *NewCommandArg("amount", AmountArg)))
+	cs.AddCommand(NewCommandDeclaration("fail_fast", "Set whether the remaining commands and script lines are skipped once one fails. Blank to view", false, NewFailFastCommand, *NewOptionalCommandArg("enabled", BoolArg)))
 	cs.AddCommand(NewCommandDeclaration("sleep", "Sleep for the given number seconds", true, NewSleepCommand, *NewCommandArg("seconds", AmountArg)))
 	cs.AddCommand(NewCommandDeclaration("exit", "Exit the wallet (quit also works)", false, NewExitCommand))
 	cs.AddCommand(NewCommandDeclaration("quit", "Synonym for exit", true, NewExitCommand))
@@ -180,14 +226,231 @@ func NewConnectCommand(inv *CommandParseResult) Command {
 
 // Execute connects to an RPC endpoint
 func (c *ConnectCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
-	rpc := cliutil.NewKoinosRPCClient(c.URL)
-	ee.RPCClient = rpc
+	configs, err := cliutil.EndpointConfigs(c.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("%w: no endpoint url given", cliutil.ErrInvalidParam)
+	}
+
+	rpc, err := cliutil.NewKoinosRPCClientWithConfig(configs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if ee.Network != nil {
+		rpc.SetChainID(ee.Network.PinnedChainID())
+	}
+
+	urls := rpc.URLs()
 
-	// TODO: Ensure connection (some sort of ping?)
-	// Issue #20
+	health, err := ee.ConnectRPCClient(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
 
 	result := NewExecutionResult()
-	result.AddMessage(fmt.Sprintf("Connected to endpoint %s", c.URL))
+	if len(urls) == 1 {
+		result.AddMessage(fmt.Sprintf("Connected to endpoint %s", urls[0]))
+	} else {
+		result.AddMessage(fmt.Sprintf("Connected to endpoints %s", strings.Join(urls, ", ")))
+	}
+
+	result.AddMessage(nodeHealthMessages(ee, health)...)
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Endpoints Command
+// ----------------------------------------------------------------------------
+
+// EndpointsCommand is a command that shows the status of each RPC endpoint
+type EndpointsCommand struct {
+}
+
+// NewEndpointsCommand creates a new endpoints object
+func NewEndpointsCommand(inv *CommandParseResult) Command {
+	return &EndpointsCommand{}
+}
+
+// Execute checks and shows the status of each RPC endpoint
+func (c *EndpointsCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot check endpoints", cliutil.ErrOffline)
+	}
+
+	endpoints := ee.RPCClient.CheckHealth(ctx)
+	current := ee.RPCClient.CurrentURL()
+
+	result := NewExecutionResult()
+	for _, endpoint := range endpoints {
+		marker := " "
+		if endpoint.URL == current {
+			marker = "*"
+		}
+
+		if endpoint.Healthy {
+			result.AddMessage(fmt.Sprintf("%s %s: healthy, head block %d, chain id %s", marker, endpoint.URL, endpoint.HeadHeight, base64.URLEncoding.EncodeToString(endpoint.ChainID)))
+		} else {
+			result.AddMessage(fmt.Sprintf("%s %s: unhealthy, %s", marker, endpoint.URL, endpoint.LastError))
+		}
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// RPC Command
+// ----------------------------------------------------------------------------
+
+// RPCCommand is a command that makes a raw rpc call
+type RPCCommand struct {
+	Method string
+	Params *string
+}
+
+// NewRPCCommand creates a new rpc object
+func NewRPCCommand(inv *CommandParseResult) Command {
+	return &RPCCommand{Method: *inv.Args["method"], Params: inv.Args["params"]}
+}
+
+// Execute makes a raw rpc call and shows the response
+func (c *RPCCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot make rpc call", cliutil.ErrOffline)
+	}
+
+	params := "{}"
+	if c.Params != nil {
+		params = *c.Params
+	}
+
+	response, err := ee.RPCClient.CallRaw(ctx, c.Method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := cliutil.ReadableJSON(response)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(s)
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// RPCTimeout Command
+// ----------------------------------------------------------------------------
+
+// RPCTimeoutCommand is a command that shows or sets the rpc call timeout and retries
+type RPCTimeoutCommand struct {
+	Seconds *string
+	Retries *string
+}
+
+// NewRPCTimeoutCommand creates a new rpc timeout object
+func NewRPCTimeoutCommand(inv *CommandParseResult) Command {
+	return &RPCTimeoutCommand{Seconds: inv.Args["seconds"], Retries: inv.Args["retries"]}
+}
+
+// Execute shows or sets the rpc call timeout and retries
+func (c *RPCTimeoutCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	result := NewExecutionResult()
+
+	if c.Seconds == nil {
+		result.AddMessage(fmt.Sprintf("RPC timeout is %s, reads are retried %d times", ee.RPCOptions.Timeout, ee.RPCOptions.Retries))
+		return result, nil
+	}
+
+	seconds, err := strconv.ParseFloat(*c.Seconds, 64)
+	if err != nil || seconds < 0 {
+		return nil, fmt.Errorf("%w: timeout must be a positive number of seconds", cliutil.ErrInvalidParam)
+	}
+
+	options := ee.RPCOptions
+	options.Timeout = time.Duration(seconds * float64(time.Second))
+
+	if c.Retries != nil {
+		retries, err := strconv.ParseUint(*c.Retries, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: retries must be a positive integer", cliutil.ErrInvalidParam)
+		}
+		options.Retries = int(retries)
+	}
+
+	ee.SetRPCOptions(options)
+
+	result.AddMessage(fmt.Sprintf("Set RPC timeout to %s, reads are retried %d times", options.Timeout, options.Retries))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Debug Command
+// ----------------------------------------------------------------------------
+
+// Debug modes
+const (
+	DebugOn     = "on"
+	DebugOff    = "off"
+	DebugDecode = "decode"
+)
+
+// DebugCommand is a command that turns debug output on or off
+type DebugCommand struct {
+	Subsystem string
+	Mode      string
+	Filename  *string
+}
+
+// NewDebugCommand creates a new debug object
+func NewDebugCommand(inv *CommandParseResult) Command {
+	return &DebugCommand{Subsystem: *inv.Args["subsystem"], Mode: *inv.Args["mode"], Filename: inv.Args["filename"]}
+}
+
+// Execute turns debug output on or off
+func (c *DebugCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if c.Subsystem != "rpc" {
+		return nil, fmt.Errorf("%w: unknown debug subsystem %s, expected rpc", cliutil.ErrInvalidParam, c.Subsystem)
+	}
+
+	var tracer *cliutil.RPCTracer
+	switch c.Mode {
+	case DebugOff:
+	case DebugOn, DebugDecode:
+		decode := c.Mode == DebugDecode
+		if c.Filename != nil {
+			var err error
+			tracer, err = cliutil.NewRPCFileTracer(*c.Filename, decode)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			tracer = cliutil.NewRPCTracer(os.Stderr, decode)
+		}
+	default:
+		return nil, fmt.Errorf("%w: debug mode must be one of (%s, %s, %s)", cliutil.ErrInvalidParam, DebugOn, DebugDecode, DebugOff)
+	}
+
+	if err := ee.SetRPCTracer(tracer); err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	switch {
+	case tracer == nil:
+		result.AddMessage("RPC tracing off")
+	case tracer.Filename() != "":
+		result.AddMessage(fmt.Sprintf("Tracing RPC calls to %s", tracer.Filename()))
+	default:
+		result.AddMessage("Tracing RPC calls")
+	}
 
 	return result, nil
 }
@@ -207,12 +470,12 @@ func NewDisconnectCommand(inv *CommandParseResult) Command {
 
 // Execute disconnects from an RPC endpoint
 func (c *DisconnectCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
-	if !ee.IsOnline() {
+	if !ee.IsConnected() {
 		return nil, fmt.Errorf("%w: cannot disconnect", cliutil.ErrOffline)
 	}
 
 	// Disconnect from the RPC endpoint
-	ee.RPCClient = nil
+	ee.SetRPCClient(nil)
 
 	result := NewExecutionResult()
 	result.AddMessage("Disconnected")
@@ -264,6 +527,7 @@ func (c *GenerateKeyCommand) Execute(ctx context.Context, ee *ExecutionEnvironme
 	result.AddMessage(fmt.Sprintf("Address: %s", base58.Encode(k.AddressBytes())))
 	result.AddMessage(fmt.Sprintf("Public : %s", base64.URLEncoding.EncodeToString(k.PublicBytes())))
 	result.AddMessage(fmt.Sprintf("Private: %s", k.Private()))
+	result.SetData(&AddressData{Address: base58.Encode(k.AddressBytes()), PublicKey: base64.URLEncoding.EncodeToString(k.PublicBytes())})
 
 	return result, nil
 }
@@ -385,6 +649,7 @@ func (c *UploadContractCommand) Execute(ctx context.Context, ee *ExecutionEnviro
 	err = ee.Session.AddOperation(op, fmt.Sprintf("Upload contract with address %s", base58.Encode(ee.Key.AddressBytes())))
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
@@ -449,6 +714,7 @@ func (c *CreateCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (
 	result := NewExecutionResult()
 	result.AddMessage(fmt.Sprintf("Created and opened new wallet: %s", c.Filename))
 	result.AddMessage(fmt.Sprintf("Address: %s", base58.Encode(key.AddressBytes())))
+	result.SetData(&AddressData{Address: base58.Encode(key.AddressBytes()), PublicKey: base64.URLEncoding.EncodeToString(key.PublicBytes())})
 
 	return result, nil
 }
@@ -512,6 +778,7 @@ func (c *ImportCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (
 	result := NewExecutionResult()
 	result.AddMessage(fmt.Sprintf("Created and opened new wallet: %s", c.Filename))
 	result.AddMessage(fmt.Sprintf("Address: %s", base58.Encode(key.AddressBytes())))
+	result.SetData(&AddressData{Address: base58.Encode(key.AddressBytes()), PublicKey: base64.URLEncoding.EncodeToString(key.PublicBytes())})
 
 	return result, nil
 }
@@ -537,6 +804,7 @@ func (c *AddressCommand) Execute(ctx context.Context, ee *ExecutionEnvironment)
 
 	result := NewExecutionResult()
 	result.AddMessage(fmt.Sprintf("Wallet address: %s", base58.Encode(ee.Key.AddressBytes())))
+	result.SetData(&AddressData{Address: base58.Encode(ee.Key.AddressBytes())})
 
 	return result, nil
 }
@@ -587,6 +855,7 @@ func (c *PublicCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (
 
 	result := NewExecutionResult()
 	result.AddMessage(fmt.Sprintf("Public key: %s", base64.URLEncoding.EncodeToString(ee.Key.PublicBytes())))
+	result.SetData(&AddressData{Address: base58.Encode(ee.Key.AddressBytes()), PublicKey: base64.URLEncoding.EncodeToString(ee.Key.PublicBytes())})
 
 	return result, nil
 }
@@ -643,23 +912,32 @@ func (c *SubmitTransactionCommand) Execute(ctx context.Context, ee *ExecutionEnv
 	}
 
 	// Decode the transaction
-	data, err := base64.URLEncoding.DecodeString(c.Transaction)
+	transaction, err := cliutil.DecodeTransaction(c.Transaction)
 	if err != nil {
 		return nil, err
 	}
 
-	transaction := &protocol.Transaction{}
-	err = proto.Unmarshal(data, transaction)
-	if err != nil {
-		return nil, err
+	if ee.Network != nil {
+		if err := ee.Network.CheckChainID(transaction.GetHeader().GetChainId()); err != nil {
+			return nil, fmt.Errorf("%w, refusing to submit", err)
+		}
 	}
 
 	receipt, err := ee.RPCClient.SubmitTransaction(ctx, transaction, true)
 	if err != nil {
+		err2 := ee.AddErrorHints(ctx, result, err)
+		if err2 != nil {
+			return result, err2
+		}
 		return result, err
 	}
 
-	result.AddMessage(cliutil.TransactionReceiptToString(receipt, len(transaction.GetOperations())))
+	result.AddMessage(cliutil.TransactionReceiptToString(receipt, len(transaction.GetOperations()), ee.Contracts))
+	result.SetData(&TransactionData{TransactionID: "0x" + hex.EncodeToString(receipt.GetId()), Receipt: protoData(receipt)})
+
+	if receipt.GetReverted() {
+		return result, fmt.Errorf("%w: 0x%s", cliutil.ErrTransactionReverted, hex.EncodeToString(receipt.GetId()))
+	}
 
 	return result, nil
 }
@@ -726,6 +1004,7 @@ func (c *CallCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*E
 	err = ee.Session.AddOperation(op, fmt.Sprintf("Call contract %s at entry point: %s with arguments %s", c.ContractID, c.EntryPoint, c.Arguments))
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
@@ -1113,9 +1392,51 @@ func NewSleepCommand(inv *CommandParseResult) Command {
 
 // Execute shows wallet address
 func (c *SleepCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	select {
+	case <-time.After(c.Duration):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
 	result := NewExecutionResult()
 	result.AddMessage(fmt.Sprintf("Slept for %s", c.Duration))
-	time.Sleep(c.Duration)
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// FailFast Command
+// ----------------------------------------------------------------------------
+
+// FailFastCommand is a command that shows or sets whether the remaining commands are skipped once one fails
+type FailFastCommand struct {
+	Enabled *string
+}
+
+// NewFailFastCommand creates a new fail fast command object
+func NewFailFastCommand(inv *CommandParseResult) Command {
+	return &FailFastCommand{Enabled: inv.Args["enabled"]}
+}
+
+// Execute shows or sets fail fast
+func (c *FailFastCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	result := NewExecutionResult()
+
+	if c.Enabled != nil {
+		enabled, err := strconv.ParseBool(*c.Enabled)
+		if err != nil {
+			return nil, fmt.Errorf("%w: enabled must be true or false", cliutil.ErrInvalidParam)
+		}
+
+		ee.FailFast = enabled
+	}
+
+	if ee.FailFast {
+		result.AddMessage("Fail fast: on, commands after a failed command are skipped")
+	} else {
+		result.AddMessage("Fail fast: off")
+	}
+	result.SetData(map[string]bool{"fail_fast": ee.FailFast})
 
 	return result, nil
 }
@@ -1191,6 +1512,7 @@ func (c *SetSystemCallCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 	err = ee.Session.AddOperation(op, fmt.Sprintf("Set system call %s to contract %s at entry point %s", c.SystemCall, c.ContractID, c.EntryPoint))
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
@@ -1260,6 +1582,7 @@ func (c *SetSystemContractCommand) Execute(ctx context.Context, ee *ExecutionEnv
 
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
@@ -1344,29 +1667,28 @@ func (c *SessionCommand) Execute(ctx context.Context, ee *ExecutionEnvironment)
 				}
 
 				// Convert to json
-				result.AddMessage("JSON:")
-				unformatedTxnJSON, err := kjson.Marshal(txn)
-				if err != nil {
-					return nil, fmt.Errorf("cannot submit transaction session, %w", err)
-				}
-				buffer := bytes.NewBuffer(make([]byte, 0))
-				err = json.Indent(buffer, unformatedTxnJSON, "", "  ")
+				txnJSON, err := cliutil.EncodeTransaction(txn, cliutil.TransactionFormatJSON)
 				if err != nil {
 					return nil, fmt.Errorf("cannot submit transaction session, %w", err)
 				}
-				txnJSON := buffer.String()
-				result.AddMessage(string(txnJSON))
+				result.AddMessage("JSON:", txnJSON)
 
 				// Convert to base64
-				data, err := proto.Marshal(txn)
+				txnBase64, err := cliutil.EncodeTransaction(txn, cliutil.TransactionFormatBase64)
 				if err != nil {
 					return nil, fmt.Errorf("cannot submit transaction session, %w", err)
 				}
-
-				result.AddMessage("\nBase64:")
-				result.AddMessage(base64.URLEncoding.EncodeToString(data))
+				result.AddMessage("\nBase64:", txnBase64)
+				result.SetData(&TransactionData{TransactionID: "0x" + hex.EncodeToString(txn.GetId()), Transaction: protoData(txn), Base64: txnBase64})
 			} else {
 				err := ee.SubmitTransaction(ctx, result, ops...)
+				if errors.Is(err, cliutil.ErrTransactionReverted) {
+					// The transaction was included, so the session is over even though it reverted
+					if err := ee.Session.EndSession(); err != nil {
+						return nil, fmt.Errorf("cannot end transaction session, %w", err)
+					}
+					return result, fmt.Errorf("error submitting transaction, %w", err)
+				}
 				if err != nil {
 					return result, fmt.Errorf("error submitting transaction, %w", err)
 				}
@@ -1395,6 +1717,7 @@ func (c *SessionCommand) Execute(ctx context.Context, ee *ExecutionEnvironment)
 		for i, op := range reqs {
 			result.AddMessage(fmt.Sprintf("%v: %s", i, op.LogMessage))
 		}
+		result.SetData(newSessionData(ee.Session))
 	default:
 		return nil, fmt.Errorf("unknown command %s, options are (begin, submit, cancel, view)", c.Command)
 	}
@@ -1409,12 +1732,14 @@ func (c *SessionCommand) Execute(ctx context.Context, ee *ExecutionEnvironment)
 // SignTransactionCommand is a command that signs a transaction with the open wallet
 type SignTransactionCommand struct {
 	Transaction string
+	Format      *string
 }
 
 // NewSignTransactionCommand signs a transacion
 func NewSignTransactionCommand(inv *CommandParseResult) Command {
 	return &SignTransactionCommand{
 		Transaction: *inv.Args["transaction"],
+		Format:      inv.Args["format"],
 	}
 }
 
@@ -1424,36 +1749,54 @@ func (c *SignTransactionCommand) Execute(ctx context.Context, ee *ExecutionEnvir
 		return nil, fmt.Errorf("%w: cannot sign transaction", cliutil.ErrWalletClosed)
 	}
 
-	trxBytes, err := base64.URLEncoding.DecodeString(c.Transaction)
-	if err != nil {
-		return nil, err
+	format := cliutil.TransactionFormatBoth
+	if c.Format != nil {
+		format = *c.Format
 	}
 
-	trx := &protocol.Transaction{}
-	err = proto.Unmarshal(trxBytes, trx)
-	if err != nil {
-		return nil, err
+	if format != cliutil.TransactionFormatBoth && format != cliutil.TransactionFormatJSON && format != cliutil.TransactionFormatBase64 {
+		return nil, fmt.Errorf("%w: format must be one of (json, base64, both)", cliutil.ErrInvalidParam)
 	}
 
-	err = util.SignTransaction(ee.Key.PrivateBytes(), trx)
+	trx, err := cliutil.DecodeTransaction(c.Transaction)
 	if err != nil {
 		return nil, err
 	}
 
-	trxBytes, err = proto.Marshal(trx)
-	if err != nil {
-		return nil, err
+	if ee.Network != nil {
+		if err := ee.Network.CheckChainID(trx.GetHeader().GetChainId()); err != nil {
+			return nil, fmt.Errorf("%w, refusing to sign", err)
+		}
 	}
 
-	jsonTrx, err := json.MarshalIndent(trx, "", "  ")
+	err = util.SignTransaction(ee.Key.PrivateBytes(), trx)
 	if err != nil {
 		return nil, err
 	}
 
-	encodedTrx := base64.URLEncoding.EncodeToString(trxBytes)
-
 	result := NewExecutionResult()
-	result.AddMessage(fmt.Sprintf("Signed Transaction:\nJSON:\n%v\nBase64:\n%v", string(jsonTrx), encodedTrx))
+	result.AddMessage("Signed Transaction:")
+	data := &TransactionData{TransactionID: "0x" + hex.EncodeToString(trx.GetId()), Transaction: protoData(trx)}
+	result.SetData(data)
+
+	if format == cliutil.TransactionFormatJSON || format == cliutil.TransactionFormatBoth {
+		jsonTrx, err := cliutil.EncodeTransaction(trx, cliutil.TransactionFormatJSON)
+		if err != nil {
+			return nil, err
+		}
+
+		result.AddMessage("JSON:", jsonTrx)
+	}
+
+	if format == cliutil.TransactionFormatBase64 || format == cliutil.TransactionFormatBoth {
+		encodedTrx, err := cliutil.EncodeTransaction(trx, cliutil.TransactionFormatBase64)
+		if err != nil {
+			return nil, err
+		}
+
+		result.AddMessage("Base64:", encodedTrx)
+		data.Base64 = encodedTrx
+	}
 
 	return result, nil
 }
@@ -1462,14 +1805,15 @@ func (c *SignTransactionCommand) Execute(ctx context.Context, ee *ExecutionEnvir
 // AccountRc Command
 // ----------------------------------------------------------------------------
 
-// AccountRcCommand is a command that retrieves a given accounts resource credits
+// AccountRcCommand is a command that retrieves a given accounts resource credits, and how they regenerate
 type AccountRcCommand struct {
 	Address *string
+	Target  *string
 }
 
 // NewAccountRcCommand creates a new GetAccountRcsCommand object
 func NewAccountRcCommand(inv *CommandParseResult) Command {
-	return &AccountRcCommand{Address: inv.Args["address"]}
+	return &AccountRcCommand{Address: inv.Args["address"], Target: inv.Args["target"]}
 }
 
 // Execute the retrieval of a given addresses resource credits
@@ -1493,19 +1837,145 @@ func (c *AccountRcCommand) Execute(ctx context.Context, ee *ExecutionEnvironment
 		}
 	}
 
-	rc, err := ee.RPCClient.GetAccountRc(ctx, address)
+	var target uint64
+	if c.Target != nil {
+		var err error
+		target, err = parseMana(*c.Target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mana, err := ee.GetManaInfo(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 
-	message := fmt.Sprintf("%d.%08d rc", rc/100000000, rc%100000000)
-
 	result := NewExecutionResult()
-	result.AddMessage(message)
+	result.AddMessage(fmt.Sprintf("Current mana: %s %s", manaString(mana.Current), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("Maximum mana: %s %s (%s balance)", manaString(mana.Max), cliutil.ManaSymbol, cliutil.KoinSymbol))
+	result.AddMessage(fmt.Sprintf("Regeneration: %s %s per day", manaString(mana.RegenerationPerDay()), cliutil.ManaSymbol))
+
+	full, _ := mana.TimeUntil(mana.Max)
+	result.AddMessage(fmt.Sprintf("Full: %s", timeUntilString(full)))
+	result.SetData(&ManaData{
+		Address:            base58.Encode(address),
+		Current:            mana.Current,
+		Max:                mana.Max,
+		RegenerationPerDay: mana.RegenerationPerDay(),
+		FullIn:             int64(full / time.Second),
+	})
+
+	if c.Target != nil {
+		wait, err := mana.TimeUntil(target)
+		if err != nil {
+			result.AddMessage(fmt.Sprintf("%s %s will never be available, it is more than the maximum mana", manaString(target), cliutil.ManaSymbol))
+		} else {
+			result.AddMessage(fmt.Sprintf("%s %s available: %s", manaString(target), cliutil.ManaSymbol, timeUntilString(wait)))
+		}
+	}
 
 	return result, nil
 }
 
+// ----------------------------------------------------------------------------
+// WaitForMana Command
+// ----------------------------------------------------------------------------
+
+// ManaPollInterval is the longest wait_for_mana waits before checking the mana again
+var ManaPollInterval = 30 * time.Second
+
+// WaitForManaCommand is a command that blocks until an account has enough mana
+type WaitForManaCommand struct {
+	Amount  string
+	Address *string
+}
+
+// NewWaitForManaCommand creates a new wait for mana command object
+func NewWaitForManaCommand(inv *CommandParseResult) Command {
+	return &WaitForManaCommand{Amount: *inv.Args["amount"], Address: inv.Args["address"]}
+}
+
+// Execute waits until the mana of the account has regenerated to the amount, checking it again as it is expected to be reached
+func (c *WaitForManaCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot wait for mana", cliutil.ErrOffline)
+	}
+
+	var address []byte
+	if c.Address == nil {
+		if !ee.IsWalletOpen() {
+			return nil, fmt.Errorf("%w: cannot wait for mana", cliutil.ErrWalletClosed)
+		}
+
+		address = ee.Key.AddressBytes()
+	} else {
+		address = base58.Decode(*c.Address)
+		if len(address) == 0 {
+			return nil, errors.New("could not parse address")
+		}
+	}
+
+	amount, err := parseMana(c.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	for {
+		mana, err := ee.GetManaInfo(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, err := mana.TimeUntil(amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s is more than the maximum mana of %s %s", cliutil.ErrInvalidAmount, c.Amount, cliutil.ManaSymbol, manaString(mana.Max), cliutil.ManaSymbol)
+		}
+
+		if wait == 0 {
+			result := NewExecutionResult()
+			result.AddMessage(fmt.Sprintf("%s %s available after %s", manaString(mana.Current), cliutil.ManaSymbol, time.Since(start).Round(time.Second)))
+			result.SetData(&ManaData{Address: base58.Encode(address), Current: mana.Current, Max: mana.Max, RegenerationPerDay: mana.RegenerationPerDay()})
+			return result, nil
+		}
+
+		if wait > ManaPollInterval {
+			wait = ManaPollInterval
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// parseMana parses a decimal amount of mana into satoshi
+func parseMana(amount string) (uint64, error) {
+	decimalAmount, err := decimal.NewFromString(amount)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", cliutil.ErrInvalidAmount, amount)
+	}
+
+	satoshi, err := util.DecimalToSatoshi(&decimalAmount, cliutil.KoinPrecision)
+	if err != nil || satoshi < 0 {
+		return 0, fmt.Errorf("%w: %s", cliutil.ErrInvalidAmount, amount)
+	}
+
+	return uint64(satoshi), nil
+}
+
+// timeUntilString describes how long until something happens
+func timeUntilString(d time.Duration) string {
+	if d == 0 {
+		return "now"
+	}
+
+	return fmt.Sprintf("in %s (%s)", d, time.Now().Add(d).Format(time.RFC3339))
+}
+
 // ----------------------------------------------------------------------------
 // AccountNonce Command
 // ----------------------------------------------------------------------------
@@ -1549,6 +2019,7 @@ func (c *AccountNonceCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 
 	result := NewExecutionResult()
 	result.AddMessage(message)
+	result.SetData(&NonceData{Address: base58.Encode(address), Nonce: nonce})
 
 	return result, nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/koinos/koinos-proto-golang/v2/encoding/text"
 	"github.com/koinos/koinos-proto-golang/v2/koinos"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/chain"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -169,6 +170,21 @@ func (c *ReadContractCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 		return nil, fmt.Errorf("%w: cannot execute method", cliutil.ErrOffline)
 	}
 
+	request, err := c.Request(ee)
+	if err != nil {
+		return nil, err
+	}
+
+	cResp, err := ee.RPCClient.ReadContract(ctx, request.Args, request.ContractId, request.EntryPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Result(ee, cResp)
+}
+
+// Request builds the read contract request from the command input
+func (c *ReadContractCommand) Request(ee *ExecutionEnvironment) (*chain.ReadContractRequest, error) {
 	contract := ee.Contracts.GetFromMethodName(c.ParseResult.CommandName)
 
 	entryPoint, err := strconv.ParseUint(ee.Contracts.GetMethod(c.ParseResult.CommandName).EntryPoint[2:], 16, 32)
@@ -191,11 +207,11 @@ func (c *ReadContractCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 	// Get the contractID
 	contractID := base58.Decode(contract.Address)
 
-	cResp, err := ee.RPCClient.ReadContract(ctx, argBytes, contractID, uint32(entryPoint))
-	if err != nil {
-		return nil, err
-	}
+	return &chain.ReadContractRequest{ContractId: contractID, EntryPoint: uint32(entryPoint), Args: argBytes}, nil
+}
 
+// Result decodes the read contract response using the method's return type
+func (c *ReadContractCommand) Result(ee *ExecutionEnvironment, cResp *chain.ReadContractResponse) (*ExecutionResult, error) {
 	// Get return message descriptor
 	md, err := ee.Contracts.GetMethodReturn(c.ParseResult.CommandName)
 	if err != nil {
@@ -210,6 +226,9 @@ func (c *ReadContractCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 
 	er := NewExecutionResult()
 
+	// Marshal the payload before the bytes are decoded for display
+	er.SetData(protoData(dMsg))
+
 	err = DecodeMessageBytes(dMsg, md)
 	if err != nil {
 		return nil, err
@@ -225,6 +244,101 @@ func (c *ReadContractCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 	return er, nil
 }
 
+// ----------------------------------------------------------------------------
+// MultiRead Command
+// ----------------------------------------------------------------------------
+
+// BatchReadCommand is a command that reads from a contract and whose read can be batched with others
+type BatchReadCommand interface {
+	Command
+	Request(ee *ExecutionEnvironment) (*chain.ReadContractRequest, error)
+	Result(ee *ExecutionEnvironment, cResp *chain.ReadContractResponse) (*ExecutionResult, error)
+}
+
+// MultiReadCommand is a command that runs several contract reads in one go
+type MultiReadCommand struct {
+	Commands string
+}
+
+// NewMultiReadCommand creates a new multi read command
+func NewMultiReadCommand(inv *CommandParseResult) Command {
+	return &MultiReadCommand{Commands: *inv.Args["commands"]}
+}
+
+// Execute runs the contract reads in batches and shows the result of each
+func (c *MultiReadCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot execute methods", cliutil.ErrOffline)
+	}
+
+	parseResults, err := ee.Parser.Parse(c.Commands)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]BatchReadCommand, len(parseResults.CommandResults))
+	requests := make([]*chain.ReadContractRequest, len(parseResults.CommandResults))
+	for i, inv := range parseResults.CommandResults {
+		expanded, err := ee.ExpandInvocation(ctx, inv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", inv.CommandName, err)
+		}
+
+		cmd, ok := expanded.Instantiate().(BatchReadCommand)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a read-only contract method", cliutil.ErrInvalidParam, inv.CommandName)
+		}
+
+		requests[i], err = cmd.Request(ee)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", inv.CommandName, err)
+		}
+		commands[i] = cmd
+	}
+
+	responses, errs := ee.RPCClient.ReadContracts(ctx, requests)
+
+	result := NewExecutionResult()
+	outputs := make([]*CommandOutput, len(parseResults.CommandResults))
+	result.SetData(outputs)
+	for i, inv := range parseResults.CommandResults {
+		result.AddMessage(parseResultString(inv) + ":")
+		outputs[i] = &CommandOutput{Command: parseResultString(inv)}
+
+		if errs[i] != nil {
+			result.AddMessage(errs[i].Error())
+			outputs[i].Error = NewCommandError(errs[i])
+			continue
+		}
+
+		r, err := commands[i].Result(ee, responses[i])
+		if err != nil {
+			result.AddMessage(err.Error())
+			outputs[i].Error = NewCommandError(err)
+			continue
+		}
+
+		result.AddMessage(r.Message...)
+		outputs[i].Success = true
+		outputs[i].Messages = r.Message
+		outputs[i].Data = r.Data
+	}
+
+	return result, nil
+}
+
+// parseResultString rebuilds the command line of a parsed command
+func parseResultString(inv *CommandParseResult) string {
+	s := inv.CommandName
+	for _, arg := range inv.Decl.Args {
+		if value := inv.Args[arg.Name]; value != nil {
+			s += " " + *value
+		}
+	}
+
+	return s
+}
+
 func DecodeMessageBytes(dMsg *dynamicpb.Message, md protoreflect.MessageDescriptor) error {
 	l := md.Fields().Len()
 	for i := 0; i < l; i++ {
@@ -349,6 +463,7 @@ func (c *WriteContractCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 	err = ee.Session.AddOperation(op, logMessage)
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
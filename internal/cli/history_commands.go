@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/contracts/token"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	account_history_rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/account_history"
+	util "github.com/koinos/koinos-util-golang/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// Account history settings
+const (
+	// DefaultAccountHistoryLimit is the number of entries shown when no limit is given
+	DefaultAccountHistoryLimit = 10
+
+	// MaxAccountHistoryLimit is the maximum number of entries that can be fetched at once
+	MaxAccountHistoryLimit = 10000
+
+	// AccountHistoryPageSize is the number of entries requested from the account history service at a time
+	AccountHistoryPageSize = 100
+)
+
+// AccountHistoryEntry is a single transaction or block affecting an account, decoded for display and export
+type AccountHistoryEntry struct {
+	Seq        uint64                 `json:"seq"`
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Height     uint64                 `json:"height,omitempty"`
+	Timestamp  string                 `json:"timestamp,omitempty"`
+	Payer      string                 `json:"payer,omitempty"`
+	Reverted   bool                   `json:"reverted,omitempty"`
+	ManaUsed   string                 `json:"mana_used,omitempty"`
+	Operations []string               `json:"operations,omitempty"`
+	Events     []AccountHistoryEvent  `json:"events,omitempty"`
+	Transfers  []AccountTokenTransfer `json:"transfers,omitempty"`
+}
+
+// AccountHistoryEvent is an event in an account history entry, with its data decoded if possible
+type AccountHistoryEvent struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Data   string `json:"data"`
+}
+
+// AccountTokenTransfer is a token transfer, mint or burn in an account history entry. From is empty for a mint and To for a burn
+type AccountTokenTransfer struct {
+	Token  string `json:"token"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Amount string `json:"amount"`
+}
+
+// String describes the transfer with the token's precision and symbol when it is registered
+func (t AccountTokenTransfer) String() string {
+	switch {
+	case t.From == "":
+		return fmt.Sprintf("Mint %s to %s", t.Amount, t.To)
+	case t.To == "":
+		return fmt.Sprintf("Burn %s from %s", t.Amount, t.From)
+	default:
+		return fmt.Sprintf("Transfer %s from %s to %s", t.Amount, t.From, t.To)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// AccountHistory Command
+// ----------------------------------------------------------------------------
+
+// AccountHistoryCommand is a command that shows the transactions and blocks affecting an account
+type AccountHistoryCommand struct {
+	Address *string
+	Limit   *string
+	From    *string
+}
+
+// NewAccountHistoryCommand creates a new account history command object
+func NewAccountHistoryCommand(inv *CommandParseResult) Command {
+	return &AccountHistoryCommand{Address: inv.Args["address"], Limit: inv.Args["limit"], From: inv.Args["from"]}
+}
+
+// Execute shows the history of an account
+func (c *AccountHistoryCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	entries, err := accountHistory(ctx, ee, c.Address, c.Limit, c.From)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	result.SetData(entries)
+	if len(entries) == 0 {
+		result.AddMessage("No account history")
+		return result, nil
+	}
+
+	for _, entry := range entries {
+		result.AddMessage(entry.String())
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// AccountHistoryExport Command
+// ----------------------------------------------------------------------------
+
+// AccountHistoryExportCommand is a command that writes the history of an account to a csv or json file
+type AccountHistoryExportCommand struct {
+	Filename string
+	Address  *string
+	Limit    *string
+	From     *string
+}
+
+// NewAccountHistoryExportCommand creates a new account history export command object
+func NewAccountHistoryExportCommand(inv *CommandParseResult) Command {
+	return &AccountHistoryExportCommand{Filename: *inv.Args["filename"], Address: inv.Args["address"], Limit: inv.Args["limit"], From: inv.Args["from"]}
+}
+
+// Execute writes the history of an account to a file, as csv or json depending on its extension
+func (c *AccountHistoryExportCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	extension := strings.ToLower(filepath.Ext(c.Filename))
+	if extension != ".csv" && extension != ".json" {
+		return nil, fmt.Errorf("%w: export file must end in .csv or .json", cliutil.ErrInvalidParam)
+	}
+
+	entries, err := accountHistory(ctx, ee, c.Address, c.Limit, c.From)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(c.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if extension == ".csv" {
+		err = writeAccountHistoryCSV(file, entries)
+	} else {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(entries)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Exported %d account history entries to %s", len(entries), c.Filename))
+
+	return result, nil
+}
+
+// accountHistory fetches and decodes the history of an account, newest first, paging through the account history service
+func accountHistory(ctx context.Context, ee *ExecutionEnvironment, addressArg *string, limitArg *string, fromArg *string) ([]*AccountHistoryEntry, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot get account history", cliutil.ErrOffline)
+	}
+
+	var address []byte
+	if addressArg == nil {
+		if !ee.IsWalletOpen() {
+			return nil, fmt.Errorf("%w: must give an address", cliutil.ErrWalletClosed)
+		}
+
+		address = ee.Key.AddressBytes()
+	} else {
+		address = base58.Decode(*addressArg)
+		if len(address) == 0 {
+			return nil, errors.New("could not parse address")
+		}
+	}
+
+	limit := uint64(DefaultAccountHistoryLimit)
+	if limitArg != nil {
+		var err error
+		limit, err = strconv.ParseUint(*limitArg, 10, 64)
+		if err != nil || limit == 0 || limit > MaxAccountHistoryLimit {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", cliutil.ErrInvalidParam, MaxAccountHistoryLimit)
+		}
+	}
+
+	var seqNum *uint64
+	if fromArg != nil {
+		from, err := strconv.ParseUint(*fromArg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be a sequence number", cliutil.ErrInvalidParam)
+		}
+		seqNum = &from
+	}
+
+	entries := make([]*AccountHistoryEntry, 0)
+	for uint64(len(entries)) < limit {
+		pageSize := limit - uint64(len(entries))
+		if pageSize > AccountHistoryPageSize {
+			pageSize = AccountHistoryPageSize
+		}
+
+		page, err := ee.RPCClient.GetAccountHistory(ctx, address, seqNum, pageSize, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range page {
+			entries = append(entries, newAccountHistoryEntry(value, ee.Contracts))
+		}
+
+		last := uint64(0)
+		if len(page) > 0 {
+			last = page[len(page)-1].SeqNum
+		}
+
+		if uint64(len(page)) < pageSize || last == 0 {
+			break
+		}
+
+		next := last - 1
+		seqNum = &next
+	}
+
+	return entries, nil
+}
+
+// newAccountHistoryEntry decodes an entry from the account history service with the registered ABIs and tokens
+func newAccountHistoryEntry(value *account_history_rpc.AccountHistoryEntry, contracts Contracts) *AccountHistoryEntry {
+	entry := &AccountHistoryEntry{Seq: value.SeqNum}
+
+	var events []*protocol.EventData
+	if trx := value.GetTrx(); trx != nil {
+		transaction := trx.GetTransaction()
+		entry.Type = "transaction"
+		entry.ID = "0x" + hex.EncodeToString(transaction.GetId())
+		entry.Payer = base58.Encode(transaction.GetHeader().GetPayer())
+
+		for _, op := range transaction.GetOperations() {
+			entry.Operations = append(entry.Operations, cliutil.OperationToString(op, contracts))
+		}
+
+		if receipt := trx.GetReceipt(); receipt != nil {
+			entry.Reverted = receipt.Reverted
+			if mana, err := util.SatoshiToDecimal(receipt.RcUsed, cliutil.KoinPrecision); err == nil {
+				entry.ManaUsed = mana.String()
+			}
+			events = receipt.Events
+		}
+	} else if block := value.GetBlock(); block != nil {
+		header := block.GetHeader()
+		entry.Type = "block"
+		entry.ID = "0x" + hex.EncodeToString(block.GetReceipt().GetId())
+		entry.Height = header.GetHeight()
+		entry.Timestamp = cliutil.TimestampToString(header.GetTimestamp())
+		events = block.GetReceipt().GetEvents()
+	}
+
+	for _, event := range events {
+		data, err := contracts.DecodeEvent(event)
+		if err != nil {
+			data = "0x" + hex.EncodeToString(event.Data)
+		}
+
+		entry.Events = append(entry.Events, AccountHistoryEvent{Name: event.Name, Source: base58.Encode(event.Source), Data: data})
+
+		if transfer, ok := tokenTransfer(event, contracts); ok {
+			entry.Transfers = append(entry.Transfers, transfer)
+		}
+	}
+
+	return entry
+}
+
+// tokenTransfer decodes a transfer, mint or burn event of a registered token, showing the amount with the token's
+// precision and symbol. Events of other contracts are only shown as events, even when they have the same name
+func tokenTransfer(event *protocol.EventData, contracts Contracts) (AccountTokenTransfer, bool) {
+	contract := contracts.GetFromAddress(base58.Encode(event.Source))
+	if contract == nil || contract.Token == nil {
+		return AccountTokenTransfer{}, false
+	}
+
+	var from, to []byte
+	var value uint64
+
+	switch event.Name {
+	case TokenTransferEventName:
+		e := &token.TransferEvent{}
+		if proto.Unmarshal(event.Data, e) != nil {
+			return AccountTokenTransfer{}, false
+		}
+		from, to, value = e.From, e.To, e.Value
+	case TokenMintEventName:
+		e := &token.MintEvent{}
+		if proto.Unmarshal(event.Data, e) != nil {
+			return AccountTokenTransfer{}, false
+		}
+		to, value = e.To, e.Value
+	case TokenBurnEventName:
+		e := &token.BurnEvent{}
+		if proto.Unmarshal(event.Data, e) != nil {
+			return AccountTokenTransfer{}, false
+		}
+		from, value = e.From, e.Value
+	default:
+		return AccountTokenTransfer{}, false
+	}
+
+	amount, err := util.SatoshiToDecimal(value, contract.Token.Precision)
+	if err != nil {
+		return AccountTokenTransfer{}, false
+	}
+
+	return AccountTokenTransfer{
+		Token:  contract.Name,
+		From:   base58.Encode(from),
+		To:     base58.Encode(to),
+		Amount: fmt.Sprintf("%s %s", amount, contract.Token.Symbol),
+	}, true
+}
+
+// String describes the entry for the account_history command
+func (e *AccountHistoryEntry) String() string {
+	var s string
+	if e.Type == "block" {
+		s = fmt.Sprintf("#%d Block %d (%s) at %s", e.Seq, e.Height, e.ID, e.Timestamp)
+	} else {
+		s = fmt.Sprintf("#%d Transaction %s paid by %s", e.Seq, e.ID, e.Payer)
+		if e.Reverted {
+			s += ", reverted"
+		}
+		if e.ManaUsed != "" {
+			s += fmt.Sprintf(", %s %s used", e.ManaUsed, cliutil.ManaSymbol)
+		}
+	}
+
+	for _, op := range e.Operations {
+		s += "\n  " + op
+	}
+
+	for _, transfer := range e.Transfers {
+		s += "\n  " + transfer.String()
+	}
+
+	for _, event := range e.Events {
+		s += fmt.Sprintf("\n  Event %s from %s: %s", event.Name, event.Source, event.Data)
+	}
+
+	return s
+}
+
+// writeAccountHistoryCSV writes one row per entry, joining the operations, transfers and events of an entry with "; "
+func writeAccountHistoryCSV(file *os.File, entries []*AccountHistoryEntry) error {
+	w := csv.NewWriter(file)
+	if err := w.Write([]string{"seq", "type", "id", "height", "timestamp", "payer", "reverted", "mana_used", "operations", "transfers", "events"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		height := ""
+		if e.Type == "block" {
+			height = strconv.FormatUint(e.Height, 10)
+		}
+
+		transfers := make([]string, len(e.Transfers))
+		for i, transfer := range e.Transfers {
+			transfers[i] = transfer.String()
+		}
+
+		events := make([]string, len(e.Events))
+		for i, event := range e.Events {
+			events[i] = fmt.Sprintf("%s from %s: %s", event.Name, event.Source, event.Data)
+		}
+
+		record := []string{
+			strconv.FormatUint(e.Seq, 10),
+			e.Type,
+			e.ID,
+			height,
+			e.Timestamp,
+			e.Payer,
+			strconv.FormatBool(e.Reverted),
+			e.ManaUsed,
+			strings.Join(e.Operations, "; "),
+			strings.Join(transfers, "; "),
+			strings.Join(events, "; "),
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
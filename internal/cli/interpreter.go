@@ -3,13 +3,19 @@ package cli
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/chain"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	util "github.com/koinos/koinos-util-golang/v2"
 	"github.com/shopspring/decimal"
@@ -35,6 +41,9 @@ type Command interface {
 type ExecutionResult struct {
 	Message      []string
 	ErrorMessage []string
+
+	// Data is the machine-readable payload of the result, shown in json output
+	Data interface{}
 }
 
 // NewExecutionResult creates a new execution result object
@@ -59,10 +68,10 @@ func (er *ExecutionResult) Print() {
 	}
 }
 
-// PrintError prints each error message in the execution result
+// PrintError prints each error message in the execution result to stderr
 func (er *ExecutionResult) PrintError() {
 	for _, m := range er.ErrorMessage {
-		fmt.Println(m)
+		fmt.Fprintln(os.Stderr, m)
 	}
 }
 
@@ -78,31 +87,171 @@ type nonceInfo struct {
 
 // ExecutionEnvironment is a struct that holds the environment for command execution.
 type ExecutionEnvironment struct {
-	RPCClient *cliutil.KoinosRPCClient
-	Key       *util.KoinosKey
-	Parser    *CommandParser
-	Contracts Contracts
-	Session   *TransactionSession
-	nonceMap  map[string]*nonceInfo
-	nonceMode string
-	rcLimit   rcInfo
-	payer     string
-	chainID   string
+	RPCClient  *cliutil.KoinosRPCClient
+	RPCOptions cliutil.RPCOptions
+	Key        *util.KoinosKey
+	Parser     *CommandParser
+	Contracts  Contracts
+	Session    *TransactionSession
+	Network    *cliutil.NetworkProfile
+	Networks   map[string]*cliutil.NetworkProfile
+	nonceMap   map[string]*nonceInfo
+	nonceMode  string
+	rcLimit    rcInfo
+	payer      string
+	chainID    string
+
+	// networkTokens are the tokens registered by the current network profile
+	networkTokens []string
+
+	monitor   *cliutil.NodeMonitor
+	heartbeat time.Duration
+
+	// ResourceLimitsFile caches the last resource limits fetched from a node, to estimate costs while offline
+	ResourceLimitsFile string
+	resourceLimits     *cliutil.CachedResourceLimits
+
+	// OutputFormat is how results are printed, either text or json
+	OutputFormat string
+
+	// FailFast skips the remaining commands once one fails
+	FailFast bool
+
+	// variables are set with set and let, and expanded in the arguments of commands
+	variables map[string]string
+
+	// sourceDepth is how many source commands are running, one inside the other
+	sourceDepth int
 }
 
 // NewExecutionEnvironment creates a new ExecutionEnvironment object
 func NewExecutionEnvironment(rpcClient *cliutil.KoinosRPCClient, parser *CommandParser) *ExecutionEnvironment {
+	var monitor *cliutil.NodeMonitor
+	if rpcClient != nil {
+		monitor = cliutil.NewNodeMonitor(rpcClient)
+	}
+
 	return &ExecutionEnvironment{
-		RPCClient: rpcClient,
-		Parser:    parser,
-		Contracts: make(map[string]*ContractInfo),
-		Session:   &TransactionSession{},
-		nonceMap:  make(map[string]*nonceInfo),
-		rcLimit:   rcInfo{value: 10000000, absolute: false},
-		payer:     SelfPayer,
-		chainID:   AutoChainID,
-		nonceMode: AutoNonce,
+		RPCClient:  rpcClient,
+		RPCOptions: cliutil.DefaultRPCOptions(),
+		Parser:     parser,
+		Contracts:  make(map[string]*ContractInfo),
+		Session:    &TransactionSession{},
+		Networks:   cliutil.DefaultNetworkProfiles(),
+		nonceMap:   make(map[string]*nonceInfo),
+		rcLimit:    rcInfo{value: 10000000, absolute: false},
+		payer:      SelfPayer,
+		chainID:    AutoChainID,
+		nonceMode:  AutoNonce,
+		monitor:    monitor,
+
+		OutputFormat: TextOutput,
+		variables:    make(map[string]string),
+	}
+}
+
+// SetOutputFormat sets how results are printed, either text or json
+func (ee *ExecutionEnvironment) SetOutputFormat(format string) error {
+	switch format {
+	case TextOutput, JSONOutput:
+		ee.OutputFormat = format
+		return nil
+	default:
+		return fmt.Errorf("%w: output must be one of (%s, %s)", cliutil.ErrInvalidParam, TextOutput, JSONOutput)
+	}
+}
+
+// SetRPCClient replaces the rpc client, closing the previous one. A nil client disconnects
+func (ee *ExecutionEnvironment) SetRPCClient(client *cliutil.KoinosRPCClient) {
+	var monitor *cliutil.NodeMonitor
+	if client != nil {
+		client.SetOptions(ee.RPCOptions)
+		monitor = cliutil.NewNodeMonitor(client)
+	}
+
+	ee.replaceRPCClient(client, monitor)
+}
+
+// ConnectRPCClient makes a handshake with the node of a new rpc client, and only replaces the current client with it
+// when the handshake succeeds. Otherwise the new client is closed and the current one is kept
+func (ee *ExecutionEnvironment) ConnectRPCClient(ctx context.Context, client *cliutil.KoinosRPCClient) (cliutil.NodeHealth, error) {
+	client.SetOptions(ee.RPCOptions)
+	monitor := cliutil.NewNodeMonitor(client)
+
+	health, err := monitor.Check(ctx)
+	if err != nil {
+		client.Close()
+		return health, err
+	}
+
+	ee.replaceRPCClient(client, monitor)
+
+	return health, nil
+}
+
+func (ee *ExecutionEnvironment) replaceRPCClient(client *cliutil.KoinosRPCClient, monitor *cliutil.NodeMonitor) {
+	if ee.monitor != nil {
+		ee.monitor.Stop()
+	}
+
+	if ee.RPCClient != nil {
+		ee.RPCClient.Close()
+	}
+
+	ee.RPCClient = client
+	ee.monitor = monitor
+	if monitor != nil && ee.heartbeat > 0 {
+		monitor.Start(ee.heartbeat)
+	}
+}
+
+// StartHeartbeat checks the node in the background at the given interval, for the current and future rpc clients
+func (ee *ExecutionEnvironment) StartHeartbeat(interval time.Duration) {
+	ee.heartbeat = interval
+	if ee.monitor != nil {
+		ee.monitor.Start(interval)
+	}
+}
+
+// CheckNode makes a handshake with the node. It returns an error when the node responds but is not a koinos node
+func (ee *ExecutionEnvironment) CheckNode(ctx context.Context) (cliutil.NodeHealth, error) {
+	if ee.monitor == nil {
+		return cliutil.NodeHealth{}, fmt.Errorf("%w: cannot check node", cliutil.ErrOffline)
+	}
+
+	return ee.monitor.Check(ctx)
+}
+
+// NodeHealth returns the result of the last handshake or heartbeat
+func (ee *ExecutionEnvironment) NodeHealth() cliutil.NodeHealth {
+	if ee.monitor == nil {
+		return cliutil.NodeHealth{}
+	}
+
+	return ee.monitor.Health()
+}
+
+// SetRPCOptions sets the timeout, retry and tracing settings for the current and future rpc clients
+func (ee *ExecutionEnvironment) SetRPCOptions(options cliutil.RPCOptions) {
+	ee.RPCOptions = options
+	if ee.IsConnected() {
+		ee.RPCClient.SetOptions(options)
+	}
+}
+
+// SetRPCTracer replaces the rpc tracer, closing the previous one. A nil tracer turns tracing off
+func (ee *ExecutionEnvironment) SetRPCTracer(tracer *cliutil.RPCTracer) error {
+	previous := ee.RPCOptions.Tracer
+
+	options := ee.RPCOptions
+	options.Tracer = tracer
+	ee.SetRPCOptions(options)
+
+	if previous != nil {
+		return previous.Close()
 	}
+
+	return nil
 }
 
 // OpenWallet opens a wallet
@@ -191,13 +340,27 @@ func (ee *ExecutionEnvironment) IsChainIDAuto() bool {
 	return ee.chainID == AutoChainID
 }
 
-// GetChainID returns the current chain ID
+// GetChainID returns the current chain ID. When a network profile pins the chain ID, any other chain ID is an error
 func (ee *ExecutionEnvironment) GetChainID(ctx context.Context) ([]byte, error) {
+	var chainID []byte
+	var err error
 	if ee.IsChainIDAuto() {
-		return ee.RPCClient.GetChainID(ctx)
+		chainID, err = ee.RPCClient.GetChainID(ctx)
+	} else {
+		chainID, err = base64.URLEncoding.DecodeString(ee.chainID)
 	}
 
-	return base64.URLEncoding.DecodeString(ee.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ee.Network != nil {
+		if err := ee.Network.CheckChainID(chainID); err != nil {
+			return nil, fmt.Errorf("%w, refusing to sign", err)
+		}
+	}
+
+	return chainID, nil
 }
 
 // GetRcLimit returns the current RC limit
@@ -231,7 +394,8 @@ func (ee *ExecutionEnvironment) GetRcLimit(ctx context.Context) (uint64, error)
 	return res, nil
 }
 
-// SubmitTransaction is a utility function to submit a transaction from a command
+// SubmitTransaction is a utility function to submit a transaction from a command. A transaction that was included, but
+// reverted, is returned as an error after its receipt is added to the result
 func (ee *ExecutionEnvironment) SubmitTransaction(ctx context.Context, result *ExecutionResult, ops ...*protocol.Operation) error {
 	// Fetch the nonce
 	subParams, err := ee.GetSubmissionParams(ctx)
@@ -242,23 +406,50 @@ func (ee *ExecutionEnvironment) SubmitTransaction(ctx context.Context, result *E
 	receipt, err := ee.RPCClient.SubmitTransactionOpsWithPayer(ctx, ops, ee.Key, subParams, ee.GetPayerAddress(), true)
 	if err != nil {
 		ee.ResetNonce()
-		if err.Error() == "insufficient rc" {
-			err2 := ee.createInsufficientRCMessage(ctx, result)
-			if err2 != nil {
-				return err2
-			}
+		err2 := ee.AddErrorHints(ctx, result, err)
+		if err2 != nil {
+			return err2
 		}
 		return err
 	}
 
-	result.AddMessage(cliutil.TransactionReceiptToString(receipt, len(ops)))
+	result.AddMessage(cliutil.TransactionReceiptToString(receipt, len(ops), ee.Contracts))
+	result.SetData(&TransactionData{TransactionID: "0x" + hex.EncodeToString(receipt.GetId()), Receipt: protoData(receipt)})
+
+	if receipt.GetReverted() {
+		return fmt.Errorf("%w: 0x%s", cliutil.ErrTransactionReverted, hex.EncodeToString(receipt.GetId()))
+	}
+
+	return nil
+}
+
+// AddErrorHints adds targeted hints to the result based on the chain error code of a failed rpc call
+func (ee *ExecutionEnvironment) AddErrorHints(ctx context.Context, result *ExecutionResult, err error) error {
+	var rpcErr cliutil.KoinosRPCError
+	if !errors.As(err, &rpcErr) {
+		return nil
+	}
+
+	switch rpcErr.Code {
+	case chain.ErrorCode_insufficient_rc:
+		if ee.IsWalletOpen() && ee.IsOnline() {
+			return ee.createInsufficientRCMessage(ctx, result)
+		}
+		result.AddErrorMessage("More mana is required to submit this transaction. Check the payer's mana with account_rc.")
+	case chain.ErrorCode_authorization_failure:
+		result.AddErrorMessage("The transaction was not authorized. Check that the open wallet (or the payer) is allowed to perform this operation.")
+	case chain.ErrorCode_invalid_nonce:
+		result.AddErrorMessage("The transaction nonce does not match the account nonce. Check it with account_nonce, or use 'nonce auto'.")
+	case chain.ErrorCode_unknown_system_call:
+		result.AddErrorMessage("The node does not recognize a system call used by this transaction. Check that the node is up to date.")
+	}
 
 	return nil
 }
 
 func (ee *ExecutionEnvironment) createInsufficientRCMessage(ctx context.Context, result *ExecutionResult) error {
 	if ee.rcLimit.absolute {
-		rc, err := ee.RPCClient.GetAccountRc(ctx, ee.Key.AddressBytes())
+		rc, err := ee.RPCClient.GetAccountRc(ctx, ee.GetPayerAddress())
 		if err != nil {
 			return err
 		}
@@ -279,7 +470,7 @@ func (ee *ExecutionEnvironment) createInsufficientRCMessage(ctx context.Context,
 			}
 
 			result.AddErrorMessage(fmt.Sprintf("Current RC limit: %v, RC available: %v", decValue, decRc))
-			result.AddErrorMessage(fmt.Sprintf("Try using a higher RC limit. Example: rclimit %v", suggestVal))
+			result.AddErrorMessage(fmt.Sprintf("Try using a higher RC limit, e.g. rclimit %v", suggestVal))
 		} else {
 			result.AddErrorMessage("You are already using the maximum RC limit, more RC is required to submit this transaction.")
 		}
@@ -298,7 +489,7 @@ func (ee *ExecutionEnvironment) createInsufficientRCMessage(ctx context.Context,
 			}
 
 			result.AddErrorMessage(fmt.Sprintf("Current rc limit: %v%%", resultVal))
-			result.AddErrorMessage(fmt.Sprintf("Try using a higher RC limit. Example: rclimit %v%%", suggestVal))
+			result.AddErrorMessage(fmt.Sprintf("Try using a higher RC limit, e.g. rclimit %v%%", suggestVal))
 		} else {
 			result.AddErrorMessage("You are already using the maximum RC limit, more RC is required to submit this transaction.")
 		}
@@ -309,6 +500,12 @@ func (ee *ExecutionEnvironment) createInsufficientRCMessage(ctx context.Context,
 
 // GetSubmissionParams returns the submission parameters for a command
 func (ee *ExecutionEnvironment) GetSubmissionParams(ctx context.Context) (*cliutil.SubmissionParams, error) {
+	// The chain ID is checked first so the nonce is not used up when signing is refused
+	chainID, err := ee.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	nonce, err := ee.GetNextNonce(ctx, true)
 	if err != nil {
 		return nil, err
@@ -322,6 +519,7 @@ func (ee *ExecutionEnvironment) GetSubmissionParams(ctx context.Context) (*cliut
 	return &cliutil.SubmissionParams{
 		Nonce:   nonce,
 		RCLimit: rcLimit,
+		ChainID: chainID,
 	}, nil
 }
 
@@ -330,23 +528,42 @@ func (ee *ExecutionEnvironment) IsWalletOpen() bool {
 	return ee.Key != nil
 }
 
-// IsOnline returns a bool representing whether or not the wallet is online
-func (ee *ExecutionEnvironment) IsOnline() bool {
+// IsConnected returns a bool representing whether or not there is an rpc client, whether or not its node is reachable
+func (ee *ExecutionEnvironment) IsConnected() bool {
 	return ee.RPCClient != nil
 }
 
+// IsOnline returns a bool representing whether or not the wallet is online. A node that failed its last check is not
+// online until a later check reaches it. Without the heartbeat, such as in scripts, the node is checked again here, at
+// most once every RecheckInterval
+func (ee *ExecutionEnvironment) IsOnline() bool {
+	if !ee.IsConnected() {
+		return false
+	}
+
+	if ee.monitor == nil {
+		return true
+	}
+
+	if ee.monitor.Running() {
+		return ee.NodeHealth().Status != cliutil.NodeUnreachable
+	}
+
+	return ee.monitor.Recheck(cliutil.WithoutTrace(context.Background()), cliutil.RecheckInterval).Status != cliutil.NodeUnreachable
+}
+
 func (ee *ExecutionEnvironment) CreateSignedTransaction(ctx context.Context, ops ...*protocol.Operation) (*protocol.Transaction, error) {
-	nonce, err := ee.GetNextNonce(ctx, true)
+	chainID, err := ee.GetChainID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	rcLimit, err := ee.GetRcLimit(ctx)
+	nonce, err := ee.GetNextNonce(ctx, true)
 	if err != nil {
 		return nil, err
 	}
 
-	chainID, err := ee.GetChainID(ctx)
+	rcLimit, err := ee.GetRcLimit(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -403,6 +620,18 @@ func NewCommandDeclaration(name string, description string, hidden bool,
 	}
 }
 
+// argIndex returns the index of the argument with the given name, or -1. Underscores in the name match dashes
+func (d *CommandDeclaration) argIndex(name string) int {
+	name = strings.ReplaceAll(name, "_", "-")
+	for i, arg := range d.Args {
+		if strings.ReplaceAll(arg.Name, "_", "-") == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // CommandArg is a struct that holds an argument for a command
 type CommandArg struct {
 	Name     string
@@ -443,12 +672,23 @@ func (arg *CommandArg) String() string {
 // InterpretResults is a struct that holds the results of a multi-command interpretation
 type InterpretResults struct {
 	Results []string
+
+	// Outputs are the machine-readable results, one for each command invocation
+	Outputs []*CommandOutput
+
+	// Format is how the results are printed, either text or json
+	Format string
+
+	// Halted is true if the remaining commands were skipped, because the user cancelled, a command failed when failing
+	// fast, or an assertion failed
+	Halted bool
 }
 
 // NewInterpretResults creates a new InterpretResults object
 func NewInterpretResults() *InterpretResults {
-	ir := &InterpretResults{}
+	ir := &InterpretResults{Format: TextOutput}
 	ir.Results = make([]string, 0)
+	ir.Outputs = make([]*CommandOutput, 0)
 	return ir
 }
 
@@ -457,35 +697,93 @@ func (ir *InterpretResults) AddResult(result ...string) {
 	ir.Results = append(ir.Results, result...)
 }
 
-// Print prints the results of a command interpretation
+// Append adds the results of another interpretation to the InterpretResults
+func (ir *InterpretResults) Append(other *InterpretResults) {
+	ir.Results = append(ir.Results, other.Results...)
+	ir.Outputs = append(ir.Outputs, other.Outputs...)
+	ir.Halted = ir.Halted || other.Halted
+}
+
+// Failed returns true if any command failed, either to parse or to execute
+func (ir *InterpretResults) Failed() bool {
+	for _, output := range ir.Outputs {
+		if !output.Success {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns the error of the first command that failed, or nil
+func (ir *InterpretResults) Err() error {
+	for _, output := range ir.Outputs {
+		if !output.Success {
+			return output.err
+		}
+	}
+
+	return nil
+}
+
+// Print prints the results of a command interpretation, as one json object per command in json output. In text output,
+// the results of failed commands are printed to stderr
 func (ir *InterpretResults) Print() {
-	for _, result := range ir.Results {
-		fmt.Println(result)
+	if ir.Format == JSONOutput {
+		for _, output := range ir.Outputs {
+			b, err := json.Marshal(output)
+			if err != nil {
+				b, _ = json.Marshal(&CommandOutput{Command: output.Command, Error: NewCommandError(err)})
+			}
+			fmt.Println(string(b))
+		}
+		return
 	}
 
-	// If there were results, skip a line at the end for readability
+	for _, output := range ir.Outputs {
+		w := os.Stdout
+		if !output.Success {
+			w = os.Stderr
+		}
+
+		for _, line := range output.text {
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	// If there were results, skip a line at the end for readability. It always goes to stdout, so that stderr only
+	// holds errors
 	if len(ir.Results) > 0 {
 		fmt.Println("")
 	}
 }
 
 // Interpret interprets and executes the results of a command parse
-func (pr *ParseResults) Interpret(ee *ExecutionEnvironment) *InterpretResults {
+func (pr *ParseResults) Interpret(ctx context.Context, ee *ExecutionEnvironment) *InterpretResults {
+	return pr.interpret(ctx, ee, "", 0)
+}
+
+// interpret executes the results of a command parse read from the given line of a script file, if any
+func (pr *ParseResults) interpret(ctx context.Context, ee *ExecutionEnvironment, file string, line int) *InterpretResults {
 	output := NewInterpretResults()
 
 	for _, inv := range pr.CommandResults {
-		cmd := inv.Instantiate()
-		result, err := cmd.Execute(context.Background(), ee)
-		if err != nil {
-			output.AddResult(err.Error())
-			if result != nil {
-				output.AddResult(result.ErrorMessage...)
-			}
-		} else {
-			output.AddResult(result.Message...)
+		result, err := ee.ExecuteInvocation(ctx, inv)
+		if err != nil && file != "" {
+			err = &ScriptError{File: file, Line: line, Err: err}
+		}
+		output.AddCommandResult(ctx, inv.CommandName, result, err)
+
+		// Skip the remaining commands once the user has cancelled, an assertion fails, or one fails when failing fast
+		if err != nil && (ctx.Err() != nil || ee.FailFast || errors.Is(err, cliutil.ErrAssertionFailed)) {
+			output.Halted = true
+			break
 		}
 	}
 
+	// Set after executing, so a command changing the output format applies to its own result
+	output.Format = ee.OutputFormat
+
 	return output
 }
 
@@ -525,20 +823,121 @@ func (pr *ParseResults) Metrics() *ParseResultMetrics {
 }
 
 // ParseAndInterpret is a helper function to parse and interpret the given command string
-func ParseAndInterpret(parser *CommandParser, ee *ExecutionEnvironment, input string) *InterpretResults {
+func ParseAndInterpret(ctx context.Context, parser *CommandParser, ee *ExecutionEnvironment, input string) *InterpretResults {
+	return parseAndInterpret(ctx, parser, ee, input, "", 0)
+}
+
+// parseAndInterpret parses and interprets a command string read from the given line of a script file, if any
+func parseAndInterpret(ctx context.Context, parser *CommandParser, ee *ExecutionEnvironment, input string, file string, line int) *InterpretResults {
 	result, err := parser.Parse(input)
 	if err != nil {
 		o := NewInterpretResults()
-		o.AddResult(err.Error())
+		o.Format = ee.OutputFormat
+		o.Halted = ee.FailFast
+
+		// The token may be on a later line of a command spanning several lines
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.File = file
+			parseErr.Line = line + strings.Count(parseErr.Input[:parseErr.Offset], "\n")
+		}
+
+		output := &CommandOutput{Error: NewCommandError(err), err: err}
+		o.Outputs = append(o.Outputs, output)
+		output.addText(o, err.Error())
+
+		// Point at the token the parser failed at
+		if parseErr != nil {
+			output.addText(o, parseErr.InputLine(), parseErr.Caret())
+		}
+
 		metrics := result.Metrics()
 		// Display help for the command if it is a valid command
 		if len(result.CommandResults) > 0 && result.CommandResults[metrics.CurrentResultIndex].Decl != nil {
-			o.AddResult("Usage: " + result.CommandResults[metrics.CurrentResultIndex].Decl.String())
+			decl := result.CommandResults[metrics.CurrentResultIndex].Decl
+			output.Command = decl.Name
+			output.Error.Usage = decl.String()
+			output.addText(o, "Usage: "+decl.String())
 		} else {
-			o.AddResult("Type \"list\" for a list of commands.")
+			output.addText(o, "Type \"list\" for a list of commands.")
 		}
+
 		return o
 	}
 
-	return result.Interpret(ee)
+	return result.interpret(ctx, ee, file, line)
+}
+
+// GetResourceLimits returns the resource limits of the chain, caching them to estimate costs while offline. When the
+// node cannot give them, the cached limits are returned instead and live is false
+func (ee *ExecutionEnvironment) GetResourceLimits(ctx context.Context) (cached *cliutil.CachedResourceLimits, live bool, err error) {
+	if ee.IsOnline() {
+		cached, err = ee.fetchResourceLimits(ctx)
+		if err == nil {
+			return cached, true, nil
+		}
+	}
+
+	if ee.resourceLimits == nil && ee.ResourceLimitsFile != "" {
+		ee.resourceLimits, _ = cliutil.LoadResourceLimits(ee.ResourceLimitsFile)
+	}
+
+	if ee.resourceLimits == nil {
+		if err != nil {
+			return nil, false, err
+		}
+
+		return nil, false, fmt.Errorf("%w: no cached resource limits, run resource_limits while online", cliutil.ErrOffline)
+	}
+
+	if ee.Network != nil {
+		if err := ee.Network.CheckChainID(ee.resourceLimits.ChainID); err != nil {
+			return nil, false, fmt.Errorf("cached resource limits are for another chain, %w", err)
+		}
+	}
+
+	return ee.resourceLimits, false, nil
+}
+
+// fetchResourceLimits gets the resource limits from the node and caches them, in the cache file if there is one
+func (ee *ExecutionEnvironment) fetchResourceLimits(ctx context.Context) (*cliutil.CachedResourceLimits, error) {
+	limits, err := ee.RPCClient.GetResourceLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := ee.RPCClient.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ee.resourceLimits = &cliutil.CachedResourceLimits{Limits: limits, ChainID: chainID, Fetched: time.Now()}
+
+	// The cache is only an aid for offline estimates, so failing to write it does not fail the command
+	if ee.ResourceLimitsFile != "" {
+		_ = cliutil.SaveResourceLimits(ee.ResourceLimitsFile, ee.resourceLimits)
+	}
+
+	return ee.resourceLimits, nil
+}
+
+// GetManaInfo returns the current mana of an address and its maximum mana, which is its KOIN balance. The KOIN contract
+// is the one registered as koin, such as by a network profile, or the mainnet contract otherwise
+func (ee *ExecutionEnvironment) GetManaInfo(ctx context.Context, address []byte) (*cliutil.ManaInfo, error) {
+	koinAddress := cliutil.KoinContractID
+	if koin, ok := ee.Contracts["koin"]; ok && koin.Token != nil {
+		koinAddress = koin.Address
+	}
+
+	current, err := ee.RPCClient.GetAccountRc(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	max, err := ee.RPCClient.GetAccountBalance(ctx, address, base58.Decode(koinAddress), cliutil.KoinBalanceOfEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cliutil.ManaInfo{Current: current, Max: max}, nil
 }
@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+// Mempool command actions
+const (
+	MempoolPending = "pending"
+	MempoolCheck   = "check"
+	MempoolNonce   = "nonce"
+)
+
+const (
+	// MaxPendingTransactions is the maximum number of pending transactions fetched from the mempool
+	MaxPendingTransactions = 2000
+)
+
+// ----------------------------------------------------------------------------
+// Mempool Command
+// ----------------------------------------------------------------------------
+
+// MempoolCommand is a command that inspects the pending transactions in the mempool
+type MempoolCommand struct {
+	Action string
+	Target *string
+}
+
+// NewMempoolCommand creates a new mempool command object
+func NewMempoolCommand(inv *CommandParseResult) Command {
+	return &MempoolCommand{Action: *inv.Args["action"], Target: inv.Args["target"]}
+}
+
+// Execute lists pending transactions, checks for a pending transaction, or shows the nonces of an account
+func (c *MempoolCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsOnline() {
+		return nil, fmt.Errorf("%w: cannot inspect mempool", cliutil.ErrOffline)
+	}
+
+	switch c.Action {
+	case MempoolPending:
+		return c.pending(ctx, ee)
+	case MempoolCheck:
+		return c.check(ctx, ee)
+	case MempoolNonce:
+		return c.nonce(ctx, ee)
+	default:
+		return nil, fmt.Errorf("%w: mempool action must be one of (%s, %s, %s)", cliutil.ErrInvalidParam, MempoolPending, MempoolCheck, MempoolNonce)
+	}
+}
+
+// pending lists the pending transactions, only those paid by or for the address if one is given
+func (c *MempoolCommand) pending(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	var address []byte
+	if c.Target != nil {
+		address = base58.Decode(*c.Target)
+		if len(address) == 0 {
+			return nil, errors.New("could not parse address")
+		}
+	}
+
+	pending, err := ee.RPCClient.GetPendingTransactions(ctx, MaxPendingTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	data := make([]json.RawMessage, 0)
+	for _, trx := range pending {
+		header := trx.GetTransaction().GetHeader()
+		if address != nil && !bytes.Equal(header.GetPayer(), address) && !bytes.Equal(pendingNonceAccount(trx.GetTransaction()), address) {
+			continue
+		}
+
+		result.AddMessage(pendingTransactionString(trx.GetTransaction(), ee.Contracts))
+		data = append(data, protoData(trx))
+	}
+	result.SetData(data)
+
+	if len(result.Message) == 0 {
+		result.AddMessage("No pending transactions")
+	}
+
+	return result, nil
+}
+
+// check shows whether a transaction is pending, and if not, whether it was included in a block
+func (c *MempoolCommand) check(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if c.Target == nil {
+		return nil, fmt.Errorf("%w: transaction id is required", cliutil.ErrInvalidParam)
+	}
+
+	transactionID, err := util.HexStringToBytes(*c.Target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: transaction id must be a hex string", cliutil.ErrInvalidParam)
+	}
+
+	pending, err := ee.RPCClient.GetPendingTransactions(ctx, MaxPendingTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	for i, trx := range pending {
+		if bytes.Equal(trx.GetTransaction().GetId(), transactionID) {
+			result.AddMessage(fmt.Sprintf("Transaction is pending in the mempool (%d of %d)", i+1, len(pending)))
+			result.SetData(map[string]interface{}{"status": "pending", "transaction": protoData(trx.GetTransaction())})
+			result.AddMessage(cliutil.TransactionToString(trx.GetTransaction(), ee.Contracts))
+			return result, nil
+		}
+	}
+
+	items, err := ee.RPCClient.GetTransactionsByID(ctx, [][]byte{transactionID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 || items[0].Transaction == nil || len(items[0].ContainingBlocks) == 0 {
+		result.AddMessage("Transaction is not in the mempool or any block. It was either never received or was dropped")
+		result.SetData(map[string]interface{}{"status": "unknown"})
+		return result, nil
+	}
+
+	blocks := make([]string, len(items[0].ContainingBlocks))
+	for i, id := range items[0].ContainingBlocks {
+		blocks[i] = "0x" + hex.EncodeToString(id)
+	}
+
+	result.AddMessage(fmt.Sprintf("Transaction is not in the mempool, it was included in block %s", strings.Join(blocks, ", ")))
+	result.SetData(map[string]interface{}{"status": "included", "blocks": blocks})
+
+	return result, nil
+}
+
+// nonce compares the on-chain nonce of an account with its pending nonce, listing its pending transactions by nonce
+// to find nonces that are used twice, already used on chain, or skipped
+func (c *MempoolCommand) nonce(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	var address []byte
+	if c.Target == nil {
+		if !ee.IsWalletOpen() {
+			return nil, fmt.Errorf("%w: must give an address", cliutil.ErrWalletClosed)
+		}
+
+		address = ee.Key.AddressBytes()
+	} else {
+		address = base58.Decode(*c.Target)
+		if len(address) == 0 {
+			return nil, errors.New("could not parse address")
+		}
+	}
+
+	chainNonce, err := ee.RPCClient.GetAccountNonce(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingNonce, err := ee.RPCClient.GetPendingNonce(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := ee.RPCClient.GetPendingTransactions(ctx, MaxPendingTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	byNonce := make(map[uint64][]*protocol.Transaction)
+	for _, trx := range pending {
+		if !bytes.Equal(pendingNonceAccount(trx.GetTransaction()), address) {
+			continue
+		}
+
+		nonce, err := util.NonceBytesToUInt64(trx.GetTransaction().GetHeader().GetNonce())
+		if err != nil {
+			continue
+		}
+
+		byNonce[nonce] = append(byNonce[nonce], trx.GetTransaction())
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Account: %s", base58.Encode(address)))
+	result.AddMessage(fmt.Sprintf("On-chain nonce: %d", chainNonce))
+
+	switch {
+	case pendingNonce == 0:
+		result.AddMessage("Pending nonce: none")
+		pendingNonce = chainNonce
+	case pendingNonce <= chainNonce:
+		// The chain moved past the mempool, whose pending transactions are all rejected
+		result.AddMessage(fmt.Sprintf("Pending nonce: %d (stale, the chain is already at nonce %d)", pendingNonce, chainNonce))
+		pendingNonce = chainNonce
+	default:
+		result.AddMessage(fmt.Sprintf("Pending nonce: %d (%d ahead of chain)", pendingNonce, pendingNonce-chainNonce))
+	}
+
+	nonces := make([]uint64, 0, len(byNonce))
+	for nonce := range byNonce {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	for _, nonce := range nonces {
+		for _, trx := range byNonce[nonce] {
+			result.AddMessage(fmt.Sprintf("  Nonce %d: 0x%s", nonce, hex.EncodeToString(trx.GetId())))
+		}
+
+		switch {
+		case nonce <= chainNonce:
+			result.AddMessage(fmt.Sprintf("Warning: nonce %d is already used on chain, its pending transactions will be rejected", nonce))
+		case len(byNonce[nonce]) > 1:
+			result.AddMessage(fmt.Sprintf("Warning: nonce %d is used by %d pending transactions, only one can be included", nonce, len(byNonce[nonce])))
+		}
+	}
+
+	for nonce := chainNonce + 1; len(nonces) > 0 && nonce < nonces[len(nonces)-1]; nonce++ {
+		if _, ok := byNonce[nonce]; !ok {
+			result.AddMessage(fmt.Sprintf("Warning: nonce %d is missing, later transactions are stuck until it is used", nonce))
+		}
+	}
+
+	result.AddMessage(fmt.Sprintf("Next nonce: %d", pendingNonce+1))
+	result.SetData(map[string]interface{}{"address": base58.Encode(address), "nonce": chainNonce, "pending_nonce": pendingNonce, "next_nonce": pendingNonce + 1, "pending": nonces})
+
+	return result, nil
+}
+
+// pendingNonceAccount returns the account whose nonce a transaction uses, the payee if there is one and otherwise the payer
+func pendingNonceAccount(transaction *protocol.Transaction) []byte {
+	header := transaction.GetHeader()
+	if len(header.GetPayee()) > 0 {
+		return header.GetPayee()
+	}
+
+	return header.GetPayer()
+}
+
+// pendingTransactionString creates a single line summary of a pending transaction
+func pendingTransactionString(transaction *protocol.Transaction, decoder cliutil.OperationDecoder) string {
+	header := transaction.GetHeader()
+	s := fmt.Sprintf("0x%s payer %s", hex.EncodeToString(transaction.GetId()), base58.Encode(header.GetPayer()))
+
+	if len(header.GetPayee()) > 0 {
+		s += fmt.Sprintf(", payee %s", base58.Encode(header.GetPayee()))
+	}
+
+	if nonce, err := util.NonceBytesToUInt64(header.GetNonce()); err == nil {
+		s += fmt.Sprintf(", nonce %d", nonce)
+	}
+
+	if rcLimit, err := util.SatoshiToDecimal(header.GetRcLimit(), cliutil.KoinPrecision); err == nil {
+		s += fmt.Sprintf(", rc limit %v %s", rcLimit, cliutil.ManaSymbol)
+	}
+
+	ops := make([]string, len(transaction.GetOperations()))
+	for i, op := range transaction.GetOperations() {
+		ops[i] = cliutil.OperationToString(op, decoder)
+	}
+
+	return s + fmt.Sprintf(", %d operations: %s", len(ops), strings.Join(ops, "; "))
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-cli/internal/cliutil"
+)
+
+// Network command actions
+const (
+	NetworkList = "list"
+	NetworkUse  = "use"
+	NetworkAdd  = "add"
+)
+
+// ----------------------------------------------------------------------------
+// Network Command
+// ----------------------------------------------------------------------------
+
+// NetworkCommand is a command that lists, switches between and adds network profiles
+type NetworkCommand struct {
+	Action  *string
+	Name    *string
+	URL     *string
+	ChainID *string
+}
+
+// NewNetworkCommand creates a new network command object
+func NewNetworkCommand(inv *CommandParseResult) Command {
+	return &NetworkCommand{Action: inv.Args["action"], Name: inv.Args["name"], URL: inv.Args["url"], ChainID: inv.Args["chain_id"]}
+}
+
+// Execute lists, switches between or adds network profiles
+func (c *NetworkCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	action := NetworkList
+	if c.Action != nil {
+		action = *c.Action
+	}
+
+	switch action {
+	case NetworkList:
+		return c.list(ee), nil
+	case NetworkUse:
+		if c.Name == nil {
+			return nil, fmt.Errorf("%w: network name is required", cliutil.ErrInvalidParam)
+		}
+
+		profile, ok := ee.Networks[*c.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown network %s", cliutil.ErrInvalidParam, *c.Name)
+		}
+
+		return UseNetwork(ctx, ee, profile)
+	case NetworkAdd:
+		return c.add(ee)
+	default:
+		return nil, fmt.Errorf("%w: network action must be one of (%s, %s, %s)", cliutil.ErrInvalidParam, NetworkList, NetworkUse, NetworkAdd)
+	}
+}
+
+// list shows every network profile, marking the current one with *
+func (c *NetworkCommand) list(ee *ExecutionEnvironment) *ExecutionResult {
+	names := make([]string, 0, len(ee.Networks))
+	for name := range ee.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := NewExecutionResult()
+	for _, name := range names {
+		profile := ee.Networks[name]
+
+		marker := " "
+		if profile == ee.Network {
+			marker = "*"
+		}
+
+		urls := make([]string, len(profile.Endpoints))
+		for i, endpoint := range profile.Endpoints {
+			urls[i] = endpoint.URL
+		}
+
+		chainID := "chain id not pinned"
+		if profile.IsPinned() {
+			chainID = "chain id " + profile.ChainID
+		}
+
+		result.AddMessage(fmt.Sprintf("%s %s: %s, %s, %s", marker, profile.Name, profile.DisplayName, strings.Join(urls, ", "), chainID))
+		for _, token := range profile.TokenNames() {
+			result.AddMessage(fmt.Sprintf("    %s: %s", token, profile.Tokens[token].Address))
+		}
+	}
+
+	return result
+}
+
+// add creates a custom network profile
+func (c *NetworkCommand) add(ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if c.Name == nil || c.URL == nil {
+		return nil, fmt.Errorf("%w: network name and url are required", cliutil.ErrInvalidParam)
+	}
+
+	if _, ok := cliutil.DefaultNetworkProfiles()[*c.Name]; ok {
+		return nil, fmt.Errorf("%w: cannot replace the built in network %s", cliutil.ErrInvalidParam, *c.Name)
+	}
+
+	configs, err := cliutil.EndpointConfigs(*c.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("%w: no endpoint url given", cliutil.ErrInvalidParam)
+	}
+
+	chainID := ""
+	if c.ChainID != nil {
+		chainID = *c.ChainID
+	}
+
+	profile, err := cliutil.NewCustomNetworkProfile(*c.Name, configs, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	ee.Networks[profile.Name] = profile
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Added network %s", profile.Name))
+
+	return result, nil
+}
+
+// UseNetwork connects to the endpoints of a network profile, pins its chain id and registers its well-known tokens
+func UseNetwork(ctx context.Context, ee *ExecutionEnvironment, profile *cliutil.NetworkProfile) (*ExecutionResult, error) {
+	rpc, err := cliutil.NewKoinosRPCClientWithConfig(profile.Endpoints...)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc.SetChainID(profile.PinnedChainID())
+
+	health, err := ee.ConnectRPCClient(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
+
+	ee.Network = profile
+
+	// A chain id set by hand for the previous network would be refused by the pin
+	ee.chainID = AutoChainID
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Using network %s (%s)", profile.Name, profile.DisplayName))
+
+	for _, name := range ee.networkTokens {
+		removeTokenCommands(ee, name)
+	}
+	ee.networkTokens = nil
+
+	names := make([]string, 0, len(profile.Tokens))
+	for _, name := range profile.TokenNames() {
+		if ee.Contracts.Contains(name) {
+			result.AddMessage(fmt.Sprintf("Token %s is already registered, not replacing it with %s", name, profile.Tokens[name].Address))
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	// The tokens are read concurrently, and registered in order
+	tokens := make([]cliutil.NetworkToken, len(names))
+	errs := cliutil.FanOut(ctx, len(names), cliutil.MaxConcurrentCalls, func(ctx context.Context, i int) error {
+		var err error
+		tokens[i], err = readNetworkToken(ctx, ee.RPCClient, profile.Tokens[names[i]])
+		return err
+	})
+
+	for i, name := range names {
+		err := errs[i]
+		if err == nil {
+			err = addTokenCommands(ee, name, tokens[i].Address, base58.Decode(tokens[i].Address), tokens[i].Precision, tokens[i].Symbol)
+		}
+
+		if err != nil {
+			result.AddMessage(fmt.Sprintf("Could not register token %s: %s", name, err))
+			continue
+		}
+
+		ee.networkTokens = append(ee.networkTokens, name)
+	}
+
+	result.AddMessage(nodeHealthMessages(ee, health)...)
+
+	return result, nil
+}
+
+// readNetworkToken checks the address of a well-known token of a network, and reads its symbol and precision when the
+// profile does not have them
+func readNetworkToken(ctx context.Context, client *cliutil.KoinosRPCClient, token cliutil.NetworkToken) (cliutil.NetworkToken, error) {
+	contractID := base58.Decode(token.Address)
+	if len(contractID) == 0 {
+		return token, fmt.Errorf("%w: invalid address %s", cliutil.ErrInvalidParam, token.Address)
+	}
+
+	if token.Symbol == "" {
+		symbol, precision, err := retrieveSymbolAndDecimals(ctx, client, contractID)
+		if err != nil {
+			return token, err
+		}
+		token.Symbol, token.Precision = *symbol, *precision
+	}
+
+	return token, nil
+}
+
+// nodeHealthMessages describes the node after a handshake, warning when it is unreachable, behind, or on another chain
+// than the current network pins
+func nodeHealthMessages(ee *ExecutionEnvironment, health cliutil.NodeHealth) []string {
+	if health.Status == cliutil.NodeUnreachable {
+		return []string{fmt.Sprintf("Warning: the node is not reachable (%s). Commands will fail until it responds", health.Err)}
+	}
+
+	messages := make([]string, 0)
+
+	chainID := base64.URLEncoding.EncodeToString(health.ChainID)
+	if ee.Network != nil && ee.Network.IsPinned() {
+		if err := ee.Network.CheckChainID(health.ChainID); err != nil {
+			messages = append(messages, fmt.Sprintf("Warning: %s. Transactions will not be signed", err))
+		} else {
+			messages = append(messages, fmt.Sprintf("Chain ID: %s (verified for %s)", chainID, ee.Network.Name))
+		}
+	} else {
+		messages = append(messages, fmt.Sprintf("Chain ID: %s", chainID))
+	}
+
+	age := health.Checked.Sub(health.HeadTime).Round(time.Second)
+	messages = append(messages, fmt.Sprintf("Head block: %d at %s (%s ago)", health.HeadHeight, health.HeadTime.Format(time.RFC3339), age))
+
+	switch health.Status {
+	case cliutil.NodeStale:
+		messages = append(messages, fmt.Sprintf("Warning: the head block is %s behind, the node may be syncing or stalled", age))
+	case cliutil.NodeDegraded:
+		messages = append(messages, "Warning: some endpoints are unhealthy, check them with endpoints")
+	}
+
+	return messages
+}
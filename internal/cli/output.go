@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
+	"google.golang.org/protobuf/proto"
+)
+
+// Output formats
+const (
+	TextOutput = "text"
+	JSONOutput = "json"
+)
+
+// CommandOutput is the machine-readable result of one command invocation, printed as a single json object in json output
+type CommandOutput struct {
+	Command  string        `json:"command"`
+	Success  bool          `json:"success"`
+	Messages []string      `json:"messages,omitempty"`
+	Data     interface{}   `json:"data,omitempty"`
+	Error    *CommandError `json:"error,omitempty"`
+
+	// text is the text output of the command, printed to stdout on success and stderr on failure
+	text []string
+
+	// err is the error the command failed with
+	err error
+}
+
+// CommandError describes why a command failed
+type CommandError struct {
+	Message string `json:"message"`
+
+	// Code identifies the kind of failure, such as offline or wallet_closed, when it is known
+	Code string `json:"code,omitempty"`
+
+	// RPCCode is the chain error code of a failed rpc call
+	RPCCode string `json:"rpc_code,omitempty"`
+
+	Logs  []string `json:"logs,omitempty"`
+	Hints []string `json:"hints,omitempty"`
+	Usage string   `json:"usage,omitempty"`
+
+	// The script file and line of the command, and the position and expected type of the token it failed to parse at
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// errorCodes are the codes of the errors a command can fail with, the first match wins
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{cliutil.ErrCommandCancelled, "cancelled"},
+	{cliutil.ErrInvalidCommandName, "invalid_command_name"},
+	{cliutil.ErrUnknownCommand, "unknown_command"},
+	{cliutil.ErrNotEnoughArguments, "not_enough_arguments"},
+	{cliutil.ErrMissingParam, "missing_parameter"},
+	{cliutil.ErrUnknownParam, "unknown_parameter"},
+	{cliutil.ErrDuplicateParam, "duplicate_parameter"},
+	{cliutil.ErrInvalidParam, "invalid_parameter"},
+	{cliutil.ErrInvalidResponse, "invalid_response"},
+	{cliutil.ErrEmptyPassphrase, "empty_passphrase"},
+	{cliutil.ErrWalletExists, "wallet_exists"},
+	{cliutil.ErrWalletClosed, "wallet_closed"},
+	{cliutil.ErrWalletDecrypt, "wallet_decrypt"},
+	{cliutil.ErrInvalidPrivateKey, "invalid_private_key"},
+	{cliutil.ErrInvalidAmount, "invalid_amount"},
+	{cliutil.ErrOffline, "offline"},
+	{cliutil.ErrFileNotFound, "file_not_found"},
+	{cliutil.ErrBlankPassword, "blank_password"},
+	{cliutil.ErrInvalidABI, "invalid_abi"},
+	{cliutil.ErrUnsupportedType, "unsupported_type"},
+	{cliutil.ErrContract, "contract"},
+	{cliutil.ErrInvalidTransaction, "invalid_transaction"},
+	{cliutil.ErrUnknownEventType, "unknown_event_type"},
+	{cliutil.ErrBlockNotFound, "block_not_found"},
+	{cliutil.ErrTransactionNotFound, "transaction_not_found"},
+	{cliutil.ErrNoEndpoints, "no_endpoints"},
+	{cliutil.ErrChainIDMismatch, "chain_id_mismatch"},
+	{cliutil.ErrEndpointBehind, "endpoint_behind"},
+	{cliutil.ErrSubmissionUncertain, "submission_uncertain"},
+	{cliutil.ErrNotKoinosNode, "not_koinos_node"},
+	{cliutil.ErrInsufficientRC, "insufficient_rc"},
+	{cliutil.ErrTransactionReverted, "transaction_reverted"},
+	{cliutil.ErrUnknownVariable, "unknown_variable"},
+	{cliutil.ErrAssertionFailed, "assertion_failed"},
+	{ErrNoSession, "no_session"},
+	{ErrSesionInProgress, "session_in_progress"},
+}
+
+// NewCommandError creates the error object of a failed command
+func NewCommandError(err error) *CommandError {
+	e := &CommandError{Message: err.Error()}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			e.Code = c.code
+			break
+		}
+	}
+
+	var rpcErr cliutil.KoinosRPCError
+	if errors.As(err, &rpcErr) {
+		if e.Code == "" {
+			e.Code = "rpc"
+		}
+		if rpcErr.Code != 0 {
+			e.RPCCode = rpcErr.Code.String()
+		}
+		e.Logs = rpcErr.Logs
+	}
+
+	var scriptErr *ScriptError
+	if errors.As(err, &scriptErr) {
+		e.File = scriptErr.File
+		e.Line = scriptErr.Line
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		e.File = parseErr.File
+		e.Line = parseErr.Line
+		e.Column = parseErr.Column()
+		e.Expected = parseErr.Expected
+	}
+
+	return e
+}
+
+// AddressData is the payload of a command showing an address or key
+type AddressData struct {
+	Address   string `json:"address,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+// AmountData is the payload of a command showing a token amount, such as a balance
+type AmountData struct {
+	Address string `json:"address,omitempty"`
+
+	// Amount is the decimal amount and Value the same amount in satoshi
+	Amount string `json:"amount"`
+	Value  uint64 `json:"value"`
+	Symbol string `json:"symbol"`
+}
+
+// ManaData is the payload of a command showing the mana of an account, in satoshi
+type ManaData struct {
+	Address            string `json:"address"`
+	Current            uint64 `json:"current"`
+	Max                uint64 `json:"max"`
+	RegenerationPerDay uint64 `json:"regeneration_per_day"`
+
+	// FullIn is the number of seconds until the mana is full
+	FullIn int64 `json:"full_in"`
+}
+
+// NonceData is the payload of a command showing the nonce of an account
+type NonceData struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+// EstimateData is the payload of estimate_cost, with the estimated resources and mana in satoshi
+type EstimateData struct {
+	*cliutil.ResourceEstimate
+	Mana    uint64 `json:"mana"`
+	RcLimit uint64 `json:"rc_limit,omitempty"`
+}
+
+// TransactionData is the payload of a command that signs or submits a transaction
+type TransactionData struct {
+	TransactionID string          `json:"transaction_id"`
+	Transaction   json.RawMessage `json:"transaction,omitempty"`
+	Base64        string          `json:"base64,omitempty"`
+	Receipt       json.RawMessage `json:"receipt,omitempty"`
+}
+
+// VariableValue returns the address, which let binds to the variable
+func (d *AddressData) VariableValue() string {
+	return d.Address
+}
+
+// VariableValue returns the decimal amount, which let binds to the variable
+func (d *AmountData) VariableValue() string {
+	return d.Amount
+}
+
+// VariableValue returns the current mana as a decimal, which let binds to the variable
+func (d *ManaData) VariableValue() string {
+	return manaString(d.Current)
+}
+
+// VariableValue returns the nonce, which let binds to the variable
+func (d *NonceData) VariableValue() string {
+	return strconv.FormatUint(d.Nonce, 10)
+}
+
+// VariableValue returns the transaction id, which let binds to the variable
+func (d *TransactionData) VariableValue() string {
+	return d.TransactionID
+}
+
+// SessionData is the payload of a command that shows or adds to the transaction session
+type SessionData struct {
+	Operations []*SessionOperationData `json:"operations"`
+}
+
+// SessionOperationData is an operation in the transaction session
+type SessionOperationData struct {
+	Description string          `json:"description"`
+	Operation   json.RawMessage `json:"operation"`
+}
+
+// newSessionData creates the payload showing the operations of a session
+func newSessionData(session *TransactionSession) *SessionData {
+	data := &SessionData{Operations: make([]*SessionOperationData, 0)}
+
+	ops, err := session.GetOperations()
+	if err != nil {
+		return data
+	}
+
+	for _, op := range ops {
+		data.Operations = append(data.Operations, &SessionOperationData{Description: op.LogMessage, Operation: protoData(op.Op)})
+	}
+
+	return data
+}
+
+// SetData sets the machine-readable payload of the result, shown in json output
+func (er *ExecutionResult) SetData(data interface{}) {
+	er.Data = data
+}
+
+// protoData marshals a protobuf message as koinos json, showing bytes as their koinos types, for a result payload
+func protoData(msg proto.Message) json.RawMessage {
+	b, err := kjson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+// AddCommandResult adds the result of a command invocation, or its error, to the results
+func (ir *InterpretResults) AddCommandResult(ctx context.Context, command string, result *ExecutionResult, err error) {
+	output := &CommandOutput{Command: command, Success: err == nil, err: err}
+	ir.Outputs = append(ir.Outputs, output)
+
+	if err == nil {
+		output.Messages = result.Message
+		output.Data = result.Data
+		output.addText(ir, result.Message...)
+		return
+	}
+
+	if ctx.Err() != nil {
+		err = cliutil.ErrCommandCancelled
+		output.err = err
+		output.Error = NewCommandError(err)
+		output.addText(ir, fmt.Sprintf("%s: %s", command, err))
+		return
+	}
+
+	// Show what the command did before it failed, such as the commands run by if or for
+	if result != nil {
+		output.Messages = result.Message
+		output.addText(ir, result.Message...)
+	}
+
+	output.Error = NewCommandError(err)
+	output.addText(ir, err.Error())
+
+	// Show the logs of a failed rpc call under the error
+	if len(output.Error.Logs) > 0 {
+		output.addText(ir, "Logs:")
+		output.addText(ir, output.Error.Logs...)
+	}
+
+	if result != nil {
+		output.Error.Hints = result.ErrorMessage
+		output.addText(ir, result.ErrorMessage...)
+	}
+}
+
+// addText adds lines to the text output of the command and to the results
+func (o *CommandOutput) addText(ir *InterpretResults, lines ...string) {
+	o.text = append(o.text, lines...)
+	ir.AddResult(lines...)
+}
+
+// ----------------------------------------------------------------------------
+// Output Command
+// ----------------------------------------------------------------------------
+
+// OutputCommand is a command that shows or sets the output format
+type OutputCommand struct {
+	Format *string
+}
+
+// NewOutputCommand creates a new output command object
+func NewOutputCommand(inv *CommandParseResult) Command {
+	return &OutputCommand{Format: inv.Args["format"]}
+}
+
+// Execute shows or sets the output format
+func (c *OutputCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	result := NewExecutionResult()
+
+	if c.Format == nil {
+		result.AddMessage(fmt.Sprintf("Output: %s", ee.OutputFormat))
+		result.SetData(map[string]string{"format": ee.OutputFormat})
+		return result, nil
+	}
+
+	if err := ee.SetOutputFormat(*c.Format); err != nil {
+		return nil, err
+	}
+
+	result.AddMessage(fmt.Sprintf("Set output to %s", ee.OutputFormat))
+	result.SetData(map[string]string{"format": ee.OutputFormat})
+
+	return result, nil
+}
@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/koinos/koinos-cli/internal/cliutil"
 )
@@ -37,6 +41,7 @@ const (
 	HexArg
 	FileArg
 	ContractNameArg
+	CommandLineArg
 
 	// A parameter should never be declared as type nothing, this is only for parsing errors
 	NoArg
@@ -68,6 +73,8 @@ func (c *CommandArgType) String() string {
 		return "none"
 	case ContractNameArg:
 		return "contract-name"
+	case CommandLineArg:
+		return "command-line"
 
 	default:
 		return "unknown"
@@ -77,8 +84,105 @@ func (c *CommandArgType) String() string {
 // Characters used in parsing
 const (
 	CommandTerminator = ';'
+	CommentToken      = '#'
 )
 
+// ParseError is an error parsing a command, with the position of the token it failed at
+type ParseError struct {
+	Err error
+
+	// Input is the parsed input, and Offset the byte offset of the token in it
+	Input  string
+	Offset int
+
+	// Expected is the type of token expected, such as amount or command, if known
+	Expected string
+
+	// File and Line are the script file and line the input was read from, if any
+	File string
+	Line int
+
+	// remaining is the length of the input from the token to the end, until the offset is known
+	remaining int
+}
+
+// newArgError creates the error of an argument at the start of the given input
+func newArgError(err error, input []byte, expected string) error {
+	return &ParseError{Err: err, Expected: expected, remaining: len(input)}
+}
+
+// newParseError completes the position of an error parsing the command at the start of the given input
+func newParseError(err error, commands string, input []byte) error {
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		// Errors before the arguments are errors of the command name
+		pe = &ParseError{Err: err, remaining: len(input)}
+		if errors.Is(err, cliutil.ErrInvalidCommandName) {
+			pe.Expected = "command"
+		}
+	}
+
+	pe.Input = commands
+	pe.Offset = len(commands) - pe.remaining
+
+	return pe
+}
+
+// lineStart returns the offset of the line of the input the token is on
+func (e *ParseError) lineStart() int {
+	return strings.LastIndex(e.Input[:e.Offset], "\n") + 1
+}
+
+// InputLine returns the line of the input the token is on
+func (e *ParseError) InputLine() string {
+	line := e.Input[e.lineStart():]
+	if i := strings.Index(line, "\n"); i >= 0 {
+		line = line[:i]
+	}
+
+	return strings.TrimRight(line, "\r")
+}
+
+// Column returns the column of the token in its line, counting from 1
+func (e *ParseError) Column() int {
+	return utf8.RuneCountInString(e.Input[e.lineStart():e.Offset]) + 1
+}
+
+// Caret returns a line with a caret under the token, to print under its line of the input
+func (e *ParseError) Caret() string {
+	var b strings.Builder
+	for _, r := range e.Input[e.lineStart():e.Offset] {
+		// Keep tabs, so the caret lines up with the input
+		if r == '\t' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+
+	b.WriteString("^")
+
+	return b.String()
+}
+
+func (e *ParseError) Error() string {
+	message := e.Err.Error()
+	if e.Expected != "" {
+		message += ", expected " + e.Expected
+	}
+
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column(), message)
+	}
+
+	return fmt.Sprintf("%s at column %d", message, e.Column())
+}
+
+// Unwrap returns the error of the parser
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // CommandParseResult is the result of parsing a single command string
 type CommandParseResult struct {
 	CommandName string
@@ -86,6 +190,9 @@ type CommandParseResult struct {
 	Decl        *CommandDeclaration
 	CurrentArg  int
 	Termination TerminationStatus
+
+	// literalArgs are the arguments given in single quotes, whose variables are not expanded
+	literalArgs map[string]bool
 }
 
 // NewCommandParseResult creates a new parse result object
@@ -94,6 +201,7 @@ func NewCommandParseResult(name string) *CommandParseResult {
 		CommandName: name,
 		Args:        make(map[string]*string),
 		CurrentArg:  -1,
+		literalArgs: make(map[string]bool),
 	}
 
 	return inv
@@ -141,6 +249,8 @@ type CommandParser struct {
 	bytesRE        *regexp.Regexp
 	boolRE         *regexp.Regexp
 	hexRE          *regexp.Regexp
+	variableRE     *regexp.Regexp
+	namedArgRE     *regexp.Regexp
 }
 
 // NewCommandParser creates a new command parser
@@ -159,8 +269,10 @@ func NewCommandParser(commands *CommandSet) *CommandParser {
 	parser.uintRE = regexp.MustCompile(`^[+]?[0-9]+`)
 	parser.intRE = regexp.MustCompile(`^[+-]?[0-9]+`)
 	parser.bytesRE = regexp.MustCompile(`^[A-Za-z0-9\-_=]+`)
-	parser.boolRE = regexp.MustCompile(`^(?P<false>[Ff][Aa][Ll][Ss][Ee]|0)|(?P<true>[Tt][Rr][Uu][Ee]|1)`)
+	parser.boolRE = regexp.MustCompile(`^(?:(?P<false>[Ff][Aa][Ll][Ss][Ee]|0)|(?P<true>[Tt][Rr][Uu][Ee]|1))`)
 	parser.hexRE = regexp.MustCompile(`^0x[0-9a-fA-F]+`)
+	parser.variableRE = regexp.MustCompile(fmt.Sprintf(`^(%s)(\s|;|$)`, VariableReferenceTokens))
+	parser.namedArgRE = regexp.MustCompile(`^(--)?([A-Za-z][A-Za-z0-9_\-]*)(=|\s|;|$)`)
 
 	return parser
 }
@@ -175,15 +287,21 @@ func (p *CommandParser) Parse(commands string) (*ParseResults, error) {
 
 	// Loop until we've consumed all input
 	for len(input) > 0 {
+		// A # where a command is expected comments out the rest of the input
+		if input[0] == CommentToken {
+			break
+		}
+
 		var err error
 		var inv *CommandParseResult
 
+		start := input
 		inv, input, err = p.parseNextCommand(input)
 		if inv != nil {
 			invs.AddResult(inv)
 		}
 		if err != nil {
-			return invs, err
+			return invs, newParseError(err, commands, start)
 		}
 
 		// If latest command has no terminator or is the last command, halt parsing
@@ -210,7 +328,7 @@ func (p *CommandParser) parseNextCommand(input []byte) (*CommandParseResult, []b
 		inv.Decl = decl
 	} else {
 		p.parseSkip(input, inv, true)
-		return inv, nil, fmt.Errorf("%w", cliutil.ErrUnknownCommand)
+		return inv, nil, fmt.Errorf("%w: %s", cliutil.ErrUnknownCommand, name)
 	}
 
 	input, err = p.parseArgs(input, inv)
@@ -221,7 +339,9 @@ func (p *CommandParser) parseNextCommand(input []byte) (*CommandParseResult, []b
 	// Skip space and check termination
 	var t TerminationStatus
 	input, t, _ = p.parseSkip(input, inv, false)
-	inv.Termination = t
+	if t != NoTermination {
+		inv.Termination = t
+	}
 
 	return inv, input, nil
 }
@@ -236,74 +356,216 @@ func (p *CommandParser) parseCommandName(input []byte) ([]byte, error) {
 	return m, nil
 }
 
-// Parse a command's arguments. Returns unconsumed input
+// Parse a command's arguments. Returns unconsumed input. Arguments are given in order, or by name as --name=value or
+// name=value, and a bool argument given as --name is true. Arguments given by name are skipped by the ones given in order
 func (p *CommandParser) parseArgs(input []byte, inv *CommandParseResult) ([]byte, error) {
-	// Loop through expected arguments
-	for i, arg := range inv.Decl.Args {
-		// Skip whitespace
+	given := make([]bool, len(inv.Decl.Args))
+	next := 0  // The next argument given in order
+	last := "" // The previous argument, to report arguments melded together
+	for i := 0; ; i++ {
+		for next < len(given) && given[next] {
+			next++
+		}
+
+		// Once every argument is given in order, only arguments given by name may follow
+		if next == len(inv.Decl.Args) && !p.peekArgName(input, inv) {
+			return input, nil
+		}
+
+		// Skip whitespace, keeping the end of the previous argument to point at a missing argument
+		end := input[len(p.skipRE.Find(input)):]
 		var t TerminationStatus
 		var skip bool
-		input, t, skip = p.parseSkip(input, inv, true)
+		input, t, skip = p.parseSkip(input, inv, false)
+		if skip && next < len(inv.Decl.Args) {
+			inv.CurrentArg = next
+		}
+
 		if t != NoTermination {
+			arg := inv.Decl.Args[next]
 			if arg.Optional {
+				// The terminator is consumed here, so record it for the commands after this one
 				inv.Args[arg.Name] = nil
+				inv.Termination = t
 				return input, nil
 			}
 
-			return input, fmt.Errorf("%w: %s", cliutil.ErrMissingParam, arg.Name)
+			return input, newArgError(fmt.Errorf("%w: %s", cliutil.ErrMissingParam, arg.Name), end, arg.ArgType.String())
 		}
 
 		// If there was no skip here, then parameters have been melded together
 		if !skip {
 			if i == 0 {
-				return input, fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, inv.Decl.Args[i].Name)
+				return input, newArgError(fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, inv.Decl.Args[next].Name), input, "space")
 			}
 
-			return input, fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, inv.Decl.Args[i-1].Name)
+			return input, newArgError(fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, last), input, "space")
+		}
+
+		index, l, flag, err := p.parseArgName(input, inv, given)
+		if err != nil {
+			return input, newArgError(err, input, "")
+		}
+
+		if index < 0 {
+			index = next
+		} else {
+			inv.CurrentArg = index
+		}
+
+		input = input[l:]
+		arg := inv.Decl.Args[index]
+		given[index] = true
+		last = arg.Name
+
+		if flag {
+			val := "true"
+			inv.Args[arg.Name] = &val
+			continue
 		}
 
 		var match []byte
-		var err error
-		var l int
-
-		// Match the argument based on type
-		switch arg.ArgType {
-		case AddressArg:
-			match, l, err = p.parseAddress(input)
-		case StringArg:
-			match, l, err = p.parseString(input)
-		case AmountArg:
-			match, l, err = p.parseAmount(input)
-		case CmdNameArg:
-			match, l, err = p.parseString(input)
-		case ContractNameArg:
-			match, l, err = p.parseContractName(input)
-		case FileArg:
-			match, l, err = p.parseString(input)
-		case UIntArg:
-			match, l, err = p.parseUInt(input)
-		case IntArg:
-			match, l, err = p.parseInt(input)
-		case BytesArg:
-			match, l, err = p.parseBytes(input)
-		case BoolArg:
-			match, l, err = p.parseBool(input)
-		case HexArg:
-			match, l, err = p.parseHex(input)
+		start := input
+
+		// A variable stands in for an argument of any type, and is checked against the type once it is expanded
+		if m := p.variableRE.FindSubmatch(input); m != nil && arg.ArgType != CommandLineArg {
+			match, l = m[1], len(m[1])
+		} else {
+			match, l, err = p.parseArg(arg.ArgType, input)
+			inv.literalArgs[arg.Name] = len(input) > 0 && input[0] == '\''
 		}
 		input = input[l:] // Consume the match
 
 		// Check for error during match
 		if err != nil {
-			return input, fmt.Errorf("%w: %s", err, arg.Name)
+			return input, newArgError(fmt.Errorf("%w: %s", err, arg.Name), start, arg.ArgType.String())
 		}
 
 		// Store the argument value in the invocation
 		val := string(match)
 		inv.Args[arg.Name] = &val
 	}
+}
+
+// Parse the name of an argument given by name. Returns the index of the argument, or -1 if the argument is given in
+// order, the consumed length, and whether it is a bool flag without a value
+func (p *CommandParser) parseArgName(input []byte, inv *CommandParseResult, given []bool) (int, int, bool, error) {
+	dashed, name, l, ok := p.matchArgName(input)
+	if !ok {
+		return -1, 0, false, nil
+	}
+
+	index := inv.Decl.argIndex(name)
+
+	// name=value is only an argument name when the command has that argument, so values containing = still work
+	if index < 0 {
+		if !dashed {
+			return -1, 0, false, nil
+		}
+
+		return 0, 0, false, fmt.Errorf("%w: %s", cliutil.ErrUnknownParam, name)
+	}
+
+	arg := inv.Decl.Args[index]
+	if given[index] {
+		return 0, 0, false, fmt.Errorf("%w: %s", cliutil.ErrDuplicateParam, arg.Name)
+	}
+
+	// --name without a value is only allowed for bool arguments
+	flag := input[l-1] != '='
+	if flag && arg.ArgType != BoolArg {
+		return 0, 0, false, fmt.Errorf("%w: %s, expected --%s=<%s>", cliutil.ErrMissingParam, arg.Name, arg.Name, arg.ArgType.String())
+	}
+
+	return index, l, flag, nil
+}
+
+// Match the name of an argument given by name, either --name, --name= or name=. Returns whether the name has dashes, the
+// name, the length of the name with its dashes and =, and whether there is a name
+func (p *CommandParser) matchArgName(input []byte) (bool, string, int, bool) {
+	m := p.namedArgRE.FindSubmatch(input)
+	if m == nil {
+		return false, "", 0, false
+	}
+
+	dashed := len(m[1]) > 0
+	if string(m[3]) == "=" {
+		return dashed, string(m[2]), len(m[0]), true
+	}
+
+	// Without =, only --name is an argument name
+	return dashed, string(m[2]), len(m[1]) + len(m[2]), dashed
+}
+
+// Returns true if the input continues with an argument given by name, after whitespace
+func (p *CommandParser) peekArgName(input []byte, inv *CommandParseResult) bool {
+	skip := p.skipRE.Find(input)
+	if len(skip) == 0 {
+		return false
+	}
+
+	dashed, name, _, ok := p.matchArgName(input[len(skip):])
+	return ok && (dashed || inv.Decl.argIndex(name) >= 0)
+}
+
+// Match an argument based on its type. Returns matched argument, consumed length, and error
+func (p *CommandParser) parseArg(argType CommandArgType, input []byte) ([]byte, int, error) {
+	switch argType {
+	case AddressArg:
+		return p.parseAddress(input)
+	case StringArg, CmdNameArg, FileArg:
+		return p.parseString(input)
+	case AmountArg:
+		return p.parseAmount(input)
+	case ContractNameArg:
+		return p.parseContractName(input)
+	case UIntArg:
+		return p.parseUInt(input)
+	case IntArg:
+		return p.parseInt(input)
+	case BytesArg:
+		return p.parseBytes(input)
+	case BoolArg:
+		return p.parseBool(input)
+	case HexArg:
+		return p.parseHex(input)
+	case CommandLineArg:
+		return p.parseCommandLine(input)
+	}
+
+	return nil, 0, nil
+}
+
+// Parse the rest of a command, up to the command terminator outside of quotes. Returns matched command, consumed length,
+// and error
+func (p *CommandParser) parseCommandLine(input []byte) ([]byte, int, error) {
+	var quote byte
+	escape := false
+
+	l := 0
+	for ; l < len(input); l++ {
+		c := input[l]
+		switch {
+		case escape:
+			escape = false
+		case c == '\\':
+			escape = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == CommandTerminator:
+			return bytes.TrimSpace(input[:l]), l, nil
+		}
+	}
+
+	if quote != 0 {
+		return nil, 0, fmt.Errorf("%w (missing closing quote)", cliutil.ErrInvalidParam)
+	}
 
-	return input, nil
+	return bytes.TrimSpace(input[:l]), l, nil
 }
 
 // Parse an address. Returns matched address consumed length, and error
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+// isEstimable returns true if the command only adds operations to the session, and so can be run by estimate_cost
+func isEstimable(cmd Command) bool {
+	switch cmd.(type) {
+	case *UploadContractCommand, *CallCommand, *WriteContractCommand, *TokenTransferCommand, *SetSystemCallCommand, *SetSystemContractCommand:
+		return true
+	}
+
+	return false
+}
+
+// ----------------------------------------------------------------------------
+// ResourceLimits Command
+// ----------------------------------------------------------------------------
+
+// ResourceLimitsCommand is a command that shows the resource limits and costs of the chain
+type ResourceLimitsCommand struct {
+}
+
+// NewResourceLimitsCommand creates a new resource limits command object
+func NewResourceLimitsCommand(inv *CommandParseResult) Command {
+	return &ResourceLimitsCommand{}
+}
+
+// Execute shows the resource limits of the chain, or the cached ones when offline
+func (c *ResourceLimitsCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	cached, live, err := ee.GetResourceLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	if !live {
+		result.AddMessage(fmt.Sprintf("Offline, using resource limits cached at %s", cached.Fetched.Format(time.RFC3339)))
+	}
+
+	limits := cached.Limits
+	result.AddMessage(fmt.Sprintf("Disk storage: %d bytes per block, %s %s per byte", limits.DiskStorageLimit, manaString(limits.DiskStorageCost), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("Network bandwidth: %d bytes per block, %s %s per byte", limits.NetworkBandwidthLimit, manaString(limits.NetworkBandwidthCost), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("Compute bandwidth: %d per block, %s %s per unit", limits.ComputeBandwidthLimit, manaString(limits.ComputeBandwidthCost), cliutil.ManaSymbol))
+	result.SetData(protoData(limits))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// EstimateCost Command
+// ----------------------------------------------------------------------------
+
+// EstimateCostCommand is a command that estimates the mana of a transaction before it is signed
+type EstimateCostCommand struct {
+	Commands string
+}
+
+// NewEstimateCostCommand creates a new estimate cost command object
+func NewEstimateCostCommand(inv *CommandParseResult) Command {
+	return &EstimateCostCommand{Commands: *inv.Args["commands"]}
+}
+
+// Execute runs the commands into a scratch session, builds the transaction they would submit, and estimates its cost
+func (c *EstimateCostCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.IsWalletOpen() {
+		return nil, fmt.Errorf("%w: cannot estimate cost", cliutil.ErrWalletClosed)
+	}
+
+	ops, err := c.operations(ctx, ee)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, live, err := ee.GetResourceLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, rcLimit, err := estimateTransaction(ctx, ee, ops, cached.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payer signs too when it is not the open wallet
+	signatures := 1
+	if !bytes.Equal(transaction.Header.Payer, ee.Key.AddressBytes()) {
+		signatures++
+	}
+
+	estimate := cliutil.EstimateTransactionResources(transaction, signatures, cached.Limits)
+
+	result := NewExecutionResult()
+	if live {
+		result.AddMessage(fmt.Sprintf("Estimated cost of %d operations:", len(ops)))
+	} else {
+		result.AddMessage(fmt.Sprintf("Estimated cost of %d operations, using resource limits cached at %s:", len(ops), cached.Fetched.Format(time.RFC3339)))
+	}
+
+	result.AddMessage(fmt.Sprintf("  Network bandwidth: %d bytes, %s %s", estimate.NetworkBandwidth, manaString(estimate.NetworkMana), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("  Disk storage: ~%d bytes, %s %s", estimate.DiskStorage, manaString(estimate.DiskMana), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("  Compute bandwidth: ~%d, %s %s", estimate.ComputeBandwidth, manaString(estimate.ComputeMana), cliutil.ManaSymbol))
+	result.AddMessage(fmt.Sprintf("  Total: ~%s %s", manaString(estimate.Mana()), cliutil.ManaSymbol))
+	result.SetData(&EstimateData{ResourceEstimate: estimate, Mana: estimate.Mana(), RcLimit: rcLimit})
+
+	if rcLimit != 0 && estimate.Mana() > rcLimit {
+		result.AddMessage(fmt.Sprintf("Warning: the estimate is over the rc limit of %s %s", manaString(rcLimit), cliutil.ManaSymbol))
+	}
+
+	return result, nil
+}
+
+// operations runs the commands with a scratch session in place of the current one, and returns the operations they added
+func (c *EstimateCostCommand) operations(ctx context.Context, ee *ExecutionEnvironment) ([]*protocol.Operation, error) {
+	parseResults, err := ee.Parser.Parse(c.Commands)
+	if err != nil {
+		return nil, err
+	}
+
+	session := ee.Session
+	ee.Session = &TransactionSession{}
+	defer func() { ee.Session = session }()
+
+	if err := ee.Session.BeginSession(); err != nil {
+		return nil, err
+	}
+
+	// Every command is checked before any of them runs
+	cmds := make([]Command, len(parseResults.CommandResults))
+	for i, inv := range parseResults.CommandResults {
+		expanded, err := ee.ExpandInvocation(ctx, inv)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds[i] = expanded.Instantiate()
+		if !isEstimable(cmds[i]) {
+			return nil, fmt.Errorf("%w: cannot estimate the cost of %s", cliutil.ErrInvalidParam, inv.CommandName)
+		}
+	}
+
+	for i, cmd := range cmds {
+		if _, err := cmd.Execute(ctx, ee); err != nil {
+			return nil, fmt.Errorf("%s: %w", parseResults.CommandResults[i].CommandName, err)
+		}
+	}
+
+	pending, err := ee.Session.GetOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("%w: the commands do not create any operations", cliutil.ErrInvalidParam)
+	}
+
+	ops := make([]*protocol.Operation, len(pending))
+	for i, op := range pending {
+		ops[i] = op.Op
+	}
+
+	return ops, nil
+}
+
+// estimateTransaction builds the unsigned transaction the operations would be submitted in, returning it with the rc
+// limit if it is known. Offline, the nonce and a relative rc limit are not known, and placeholders of the same size are
+// used instead
+func estimateTransaction(ctx context.Context, ee *ExecutionEnvironment, ops []*protocol.Operation, chainID []byte) (*protocol.Transaction, uint64, error) {
+	var err error
+	if !ee.IsChainIDAuto() {
+		chainID, err = base64.URLEncoding.DecodeString(ee.chainID)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else if ee.Network != nil && ee.Network.IsPinned() {
+		chainID, err = base64.URLEncoding.DecodeString(ee.Network.ChainID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	nonce := uint64(1)
+	if !ee.IsNonceAuto() {
+		nonce, err = strconv.ParseUint(ee.nonceMode, 10, 64)
+	} else if ee.IsOnline() {
+		nonce, err = ee.GetNextNonce(ctx, false)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rcLimit := uint64(0)
+	if ee.rcLimit.absolute || ee.IsOnline() {
+		rcLimit, err = ee.GetRcLimit(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// A placeholder of typical size (1 mana) stands in for an unknown rc limit
+	placeholder := rcLimit
+	if placeholder == 0 {
+		placeholder = 100000000
+	}
+
+	transaction, err := cliutil.CreateTransaction(ctx, ops, ee.Key.AddressBytes(), nonce, placeholder, chainID, ee.GetPayerAddress())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transaction, rcLimit, nil
+}
+
+// manaString formats an amount of mana in satoshi as a decimal
+func manaString(satoshi uint64) string {
+	mana, err := util.SatoshiToDecimal(satoshi, cliutil.KoinPrecision)
+	if err != nil {
+		return strconv.FormatUint(satoshi, 10)
+	}
+
+	return mana.String()
+}
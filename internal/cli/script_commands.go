@@ -0,0 +1,677 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/koinos/koinos-cli/internal/cliutil"
+	koinos_chain "github.com/koinos/koinos-proto-golang/v2/koinos/chain"
+	"github.com/shopspring/decimal"
+)
+
+// MaxSourceDepth is how deeply source can include files, to catch a file that includes itself
+const MaxSourceDepth = 16
+
+var (
+	conditionRE = regexp.MustCompile(`^(.*?)\s*(==|!=|<=|>=|<|>)\s*(.*)$`)
+	rangeRE     = regexp.MustCompile(`^([+-]?[0-9]+)\.\.([+-]?[0-9]+)$`)
+)
+
+// EvaluateCondition evaluates a condition of if or assert. A condition is either a comparison of two values with ==, !=,
+// <, <=, > or >=, or a single value, which is false if it is empty, 0 or false. Values are compared as numbers when both
+// are numbers, and as strings otherwise
+func (ee *ExecutionEnvironment) EvaluateCondition(ctx context.Context, condition string) (bool, error) {
+	m := conditionRE.FindStringSubmatch(strings.TrimSpace(condition))
+	if m == nil {
+		value, err := ee.conditionOperand(ctx, condition)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(value) {
+		case "", "0", "false":
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	left, err := ee.conditionOperand(ctx, m[1])
+	if err != nil {
+		return false, err
+	}
+
+	right, err := ee.conditionOperand(ctx, m[3])
+	if err != nil {
+		return false, err
+	}
+
+	var cmp int
+	l, lErr := decimal.NewFromString(left)
+	r, rErr := decimal.NewFromString(right)
+	if lErr == nil && rErr == nil {
+		cmp = l.Cmp(r)
+	} else {
+		switch m[2] {
+		case "==":
+			return left == right, nil
+		case "!=":
+			return left != right, nil
+		}
+
+		return false, fmt.Errorf("%w: cannot compare %s and %s with %s, they are not numbers", cliutil.ErrInvalidParam, left, right, m[2])
+	}
+
+	switch m[2] {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+
+	return cmp >= 0, nil
+}
+
+// conditionOperand expands the variables in a value of a condition. Quotes around the value are removed, and variables in
+// single quotes are not expanded
+func (ee *ExecutionEnvironment) conditionOperand(ctx context.Context, operand string) (string, error) {
+	operand = strings.TrimSpace(operand)
+	if len(operand) >= 2 {
+		switch {
+		case operand[0] == '\'' && operand[len(operand)-1] == '\'':
+			return operand[1 : len(operand)-1], nil
+		case operand[0] == '"' && operand[len(operand)-1] == '"':
+			operand = operand[1 : len(operand)-1]
+		}
+	}
+
+	return ee.ExpandVariables(ctx, operand)
+}
+
+// RunCommands parses and executes the commands of a script block, such as the body of if or for, stopping at the first
+// command that fails. The result has the messages of the commands that ran
+func (ee *ExecutionEnvironment) RunCommands(ctx context.Context, commands string) (*ExecutionResult, error) {
+	parseResults, err := ee.Parser.Parse(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+
+	for _, inv := range parseResults.CommandResults {
+		if ctx.Err() != nil {
+			return result, cliutil.ErrCommandCancelled
+		}
+
+		r, err := ee.ExecuteInvocation(ctx, inv)
+		if r != nil {
+			result.AddMessage(r.Message...)
+			result.AddErrorMessage(r.ErrorMessage...)
+		}
+
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", inv.CommandName, err)
+		}
+	}
+
+	return result, nil
+}
+
+// ScriptError is the error of a command run from a script file, with the file and line of the command
+type ScriptError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the error of the command
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// ScriptStatement is a command string of a script file, which may span several lines
+type ScriptStatement struct {
+	Text string
+
+	// Line is the line of the file the statement starts on, counting from 1
+	Line int
+}
+
+var heredocRE = regexp.MustCompile(`^<<(['"]?)([A-Za-z_][A-Za-z0-9_]*)(['"]?)\s*$`)
+
+// SplitScript splits a script into its statements. A statement continues on the next line when its line ends with a
+// backslash or inside quotes, and <<NAME at the end of a line starts a block of lines, ended by a line with only NAME,
+// which is given to the command as a quoted argument. Variables are expanded in the block unless the name is quoted, as
+// in <<'NAME'. A # or // at the start of a line, or after a space outside quotes, comments out the rest of the line.
+// Newlines are kept in the statements, so errors can point at the line they are on
+func SplitScript(script string) ([]*ScriptStatement, error) {
+	statements := make([]*ScriptStatement, 0)
+
+	var text strings.Builder
+	start := 0     // The line the current statement starts on
+	var quote byte // The open quote, if any
+
+	// The block being read, its delimiter, and the quote it is given in
+	var block strings.Builder
+	inBlock := false
+	delimiter := ""
+	var blockQuote byte
+
+	lines := strings.Split(script, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if text.Len() == 0 && quote == 0 && !inBlock {
+			start = i + 1
+		}
+
+		if inBlock {
+			if strings.TrimSpace(line) != delimiter {
+				block.WriteString(line)
+				block.WriteString("\n")
+				continue
+			}
+
+			// Give the block as a quoted argument, without its last newline
+			body := strings.TrimSuffix(block.String(), "\n")
+			body = strings.ReplaceAll(body, "\\", "\\\\")
+			body = strings.ReplaceAll(body, string(blockQuote), "\\"+string(blockQuote))
+
+			text.WriteByte(blockQuote)
+			text.WriteString(body)
+			text.WriteByte(blockQuote)
+
+			inBlock = false
+			block.Reset()
+		} else {
+			var marker []string
+			line, marker, quote = scanScriptLine(line, quote)
+
+			switch {
+			case marker != nil:
+				// The block starts on the next line
+				text.WriteString(line)
+				inBlock = true
+				delimiter = marker[2]
+				blockQuote = '"'
+				if marker[1] == "'" {
+					blockQuote = '\''
+				}
+				continue
+			case quote != 0:
+				// The quoted string continues on the next line
+				text.WriteString(line)
+				text.WriteString("\n")
+				continue
+			case strings.HasSuffix(strings.TrimRight(line, " \t"), "\\"):
+				// Keep the newline in place of the backslash
+				line = strings.TrimRight(line, " \t")
+				text.WriteString(line[:len(line)-1])
+				text.WriteString("\n")
+				continue
+			}
+
+			text.WriteString(line)
+		}
+
+		statements = append(statements, &ScriptStatement{Text: text.String(), Line: start})
+		text.Reset()
+	}
+
+	if inBlock {
+		return statements, &ScriptError{Line: start, Err: fmt.Errorf("%w: block is not ended by %s", cliutil.ErrInvalidParam, delimiter)}
+	}
+
+	// A statement left open by quotes or a backslash is given to the parser, which reports it
+	if text.Len() > 0 {
+		statements = append(statements, &ScriptStatement{Text: text.String(), Line: start})
+	}
+
+	return statements, nil
+}
+
+// scanScriptLine removes the comment from a line of a script and finds the quote left open at its end, starting inside
+// the given quote. Returns the line, the submatches of a heredoc marker ending the line, if any, and the open quote
+func scanScriptLine(line string, quote byte) (string, []string, byte) {
+	escape := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if quote != 0 {
+			switch {
+			case escape:
+				escape = false
+			case c == '\\':
+				escape = true
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		// Comments and blocks start at the beginning of the line or after a space
+		if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+			rest := line[i:]
+			if strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "//") {
+				return line[:i], nil, 0
+			}
+
+			if m := heredocRE.FindStringSubmatch(rest); m != nil && m[1] == m[3] {
+				return line[:i], m, 0
+			}
+		}
+
+		if c == '\'' || c == '"' {
+			quote = c
+		}
+	}
+
+	return line, nil, quote
+}
+
+// InterpretFile runs the commands in a script file, one statement at a time. Statements after a failed statement still
+// run, unless an assertion failed or the environment fails fast
+func InterpretFile(ctx context.Context, ee *ExecutionEnvironment, filename string) (*InterpretResults, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", cliutil.ErrFileNotFound, filename)
+		}
+
+		return nil, err
+	}
+
+	statements, err := SplitScript(string(data))
+	if err != nil {
+		var scriptErr *ScriptError
+		if errors.As(err, &scriptErr) {
+			scriptErr.File = filename
+		}
+
+		return nil, err
+	}
+
+	results := NewInterpretResults()
+
+	for _, statement := range statements {
+		results.Append(parseAndInterpret(ctx, ee.Parser, ee, statement.Text, filename, statement.Line))
+		if results.Halted {
+			break
+		}
+	}
+
+	results.Format = ee.OutputFormat
+
+	return results, nil
+}
+
+// ----------------------------------------------------------------------------
+// If Command
+// ----------------------------------------------------------------------------
+
+// IfCommand is a command that runs commands when a condition holds
+type IfCommand struct {
+	Condition    string
+	Commands     string
+	Else         *string
+	ElseCommands *string
+}
+
+// NewIfCommand creates a new if command object
+func NewIfCommand(inv *CommandParseResult) Command {
+	return &IfCommand{Condition: *inv.Args["condition"], Commands: *inv.Args["commands"], Else: inv.Args["else"], ElseCommands: inv.Args["else-commands"]}
+}
+
+// Execute evaluates the condition, then runs the commands or the else commands
+func (c *IfCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if c.Else != nil && (*c.Else != "else" || c.ElseCommands == nil) {
+		return nil, fmt.Errorf("%w: expected if <condition> <commands> else <commands>", cliutil.ErrInvalidParam)
+	}
+
+	holds, err := ee.EvaluateCondition(ctx, c.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if holds {
+		return ee.RunCommands(ctx, c.Commands)
+	}
+
+	if c.ElseCommands != nil {
+		return ee.RunCommands(ctx, *c.ElseCommands)
+	}
+
+	return NewExecutionResult(), nil
+}
+
+// ----------------------------------------------------------------------------
+// For Command
+// ----------------------------------------------------------------------------
+
+// ForCommand is a command that runs commands for each value in a list or range
+type ForCommand struct {
+	Name     string
+	In       string
+	List     string
+	Commands string
+}
+
+// NewForCommand creates a new for command object
+func NewForCommand(inv *CommandParseResult) Command {
+	return &ForCommand{Name: *inv.Args["name"], In: *inv.Args["in"], List: *inv.Args["list"], Commands: *inv.Args["commands"]}
+}
+
+// Execute sets the variable to each value in turn and runs the commands, stopping at the first command that fails
+func (c *ForCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if c.In != "in" {
+		return nil, fmt.Errorf("%w: expected for <name> in <list> <commands>", cliutil.ErrInvalidParam)
+	}
+
+	result := NewExecutionResult()
+
+	err := eachForValue(c.List, func(value string) error {
+		if ctx.Err() != nil {
+			return cliutil.ErrCommandCancelled
+		}
+
+		if err := ee.SetVariable(c.Name, value); err != nil {
+			return err
+		}
+
+		r, err := ee.RunCommands(ctx, c.Commands)
+		if r != nil {
+			result.AddMessage(r.Message...)
+			result.AddErrorMessage(r.ErrorMessage...)
+		}
+
+		return err
+	})
+
+	return result, err
+}
+
+// eachForValue calls f with each value of a for list, stopping at the first error. The list is either a range such as
+// 1..10, which includes both ends, or values separated by spaces or commas. A range is counted as it goes, so a large one
+// takes no memory
+func eachForValue(list string, f func(string) error) error {
+	list = strings.TrimSpace(list)
+
+	if m := rangeRE.FindStringSubmatch(list); m != nil {
+		from, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, err)
+		}
+
+		to, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, err)
+		}
+
+		step := int64(1)
+		if to < from {
+			step = -1
+		}
+
+		for i := from; ; i += step {
+			if err := f(strconv.FormatInt(i, 10)); err != nil {
+				return err
+			}
+
+			if i == to {
+				return nil
+			}
+		}
+	}
+
+	values := strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	for _, value := range values {
+		if err := f(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Repeat Command
+// ----------------------------------------------------------------------------
+
+// RepeatCommand is a command that runs commands a number of times
+type RepeatCommand struct {
+	Count    string
+	Commands string
+}
+
+// NewRepeatCommand creates a new repeat command object
+func NewRepeatCommand(inv *CommandParseResult) Command {
+	return &RepeatCommand{Count: *inv.Args["count"], Commands: *inv.Args["commands"]}
+}
+
+// Execute runs the commands the given number of times, stopping at the first command that fails
+func (c *RepeatCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	count, err := strconv.ParseUint(strings.TrimPrefix(c.Count, "+"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrInvalidParam, err)
+	}
+
+	result := NewExecutionResult()
+
+	for i := uint64(0); i < count; i++ {
+		if ctx.Err() != nil {
+			return result, cliutil.ErrCommandCancelled
+		}
+
+		r, err := ee.RunCommands(ctx, c.Commands)
+		if r != nil {
+			result.AddMessage(r.Message...)
+			result.AddErrorMessage(r.ErrorMessage...)
+		}
+
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Source Command
+// ----------------------------------------------------------------------------
+
+// SourceCommand is a command that runs the commands in a script file
+type SourceCommand struct {
+	Filename string
+}
+
+// NewSourceCommand creates a new source command object
+func NewSourceCommand(inv *CommandParseResult) Command {
+	return &SourceCommand{Filename: *inv.Args["filename"]}
+}
+
+// Execute runs the script file. The command fails if any line of the file failed
+func (c *SourceCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if ee.sourceDepth >= MaxSourceDepth {
+		return nil, fmt.Errorf("%w: %s, files are sourced more than %d deep", cliutil.ErrInvalidParam, c.Filename, MaxSourceDepth)
+	}
+
+	ee.sourceDepth++
+	results, err := InterpretFile(ctx, ee, c.Filename)
+	ee.sourceDepth--
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewExecutionResult()
+	for _, output := range results.Outputs {
+		if output.Success {
+			result.AddMessage(output.text...)
+		} else {
+			result.AddErrorMessage(output.Error.Hints...)
+		}
+	}
+
+	return result, results.Err()
+}
+
+// ----------------------------------------------------------------------------
+// Assert Command
+// ----------------------------------------------------------------------------
+
+// AssertCommand is a command that fails when a condition does not hold
+type AssertCommand struct {
+	Condition string
+	Message   *string
+}
+
+// NewAssertCommand creates a new assert command object
+func NewAssertCommand(inv *CommandParseResult) Command {
+	return &AssertCommand{Condition: *inv.Args["condition"], Message: inv.Args["message"]}
+}
+
+// Execute evaluates the condition
+func (c *AssertCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	holds, err := ee.EvaluateCondition(ctx, c.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if !holds {
+		if c.Message != nil {
+			return nil, fmt.Errorf("%w: %s", cliutil.ErrAssertionFailed, *c.Message)
+		}
+
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrAssertionFailed, c.Condition)
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Assertion passed: %s", c.Condition))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Expect Revert Command
+// ----------------------------------------------------------------------------
+
+// ExpectRevertCommand is a command that fails unless the given commands are reverted by the chain
+type ExpectRevertCommand struct {
+	Command string
+}
+
+// NewExpectRevertCommand creates a new expect_revert command object
+func NewExpectRevertCommand(inv *CommandParseResult) Command {
+	return &ExpectRevertCommand{Command: *inv.Args["command"]}
+}
+
+// Execute runs the commands. A revert or rejection from the node passes, failures of the cli itself, such as being
+// offline, are returned as they are
+func (c *ExpectRevertCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	result, err := ee.RunCommands(ctx, c.Command)
+	if err == nil {
+		return result, fmt.Errorf("%w: expected %s to revert", cliutil.ErrAssertionFailed, strings.TrimSpace(c.Command))
+	}
+
+	if !isRevert(err) {
+		return result, err
+	}
+
+	result = NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Reverted as expected: %s", err))
+
+	return result, nil
+}
+
+// isRevert returns true if the error is the chain reverting or failing a transaction, either when it is submitted or in
+// the receipt of the included transaction
+func isRevert(err error) bool {
+	if errors.Is(err, cliutil.ErrTransactionReverted) {
+		return true
+	}
+
+	var rpcErr cliutil.KoinosRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == koinos_chain.ErrorCode_reversion || rpcErr.Code == koinos_chain.ErrorCode_failure
+	}
+
+	return false
+}
+
+// ----------------------------------------------------------------------------
+// Expect Balance Command
+// ----------------------------------------------------------------------------
+
+// ExpectBalanceCommand is a command that fails unless an address has the given balance of a token
+type ExpectBalanceCommand struct {
+	Token   string
+	Address string
+	Amount  string
+}
+
+// NewExpectBalanceCommand creates a new expect_balance command object
+func NewExpectBalanceCommand(inv *CommandParseResult) Command {
+	return &ExpectBalanceCommand{Token: *inv.Args["token"], Address: *inv.Args["address"], Amount: *inv.Args["amount"]}
+}
+
+// Execute reads the balance with the token's balance_of command and compares it to the amount
+func (c *ExpectBalanceCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	contract, ok := ee.Contracts[c.Token]
+	if !ok || contract.Token == nil {
+		return nil, fmt.Errorf("%w: %s is not a registered token", cliutil.ErrInvalidParam, c.Token)
+	}
+
+	expected, err := decimal.NewFromString(c.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrInvalidAmount, c.Amount)
+	}
+
+	parseResults, err := ee.Parser.Parse(fmt.Sprintf("%s.balance_of %s", c.Token, c.Address))
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := ee.ExecuteInvocation(ctx, parseResults.CommandResults[0])
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := balance.Data.(*AmountData)
+	if !ok {
+		return nil, fmt.Errorf("%w: balance of %s", cliutil.ErrInvalidResponse, c.Token)
+	}
+
+	actual, err := decimal.NewFromString(data.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", cliutil.ErrInvalidResponse, err)
+	}
+
+	if !actual.Equal(expected) {
+		return nil, fmt.Errorf("%w: expected %s to have %s %s, has %s %s", cliutil.ErrAssertionFailed, c.Address, c.Amount, data.Symbol, data.Amount, data.Symbol)
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Balance of %s is %s %s", c.Address, data.Amount, data.Symbol))
+	result.SetData(data)
+
+	return result, nil
+}
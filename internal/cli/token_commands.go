@@ -10,6 +10,7 @@ import (
 	"github.com/koinos/koinos-cli/internal/cliutil"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/contracts/token"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/chain"
 	util "github.com/koinos/koinos-util-golang/v2"
 	"github.com/shopspring/decimal"
 	"google.golang.org/protobuf/proto"
@@ -23,6 +24,13 @@ const (
 	TokenDecimalsEntry    = uint32(0xee80fd2f)
 )
 
+// Token event names
+const (
+	TokenTransferEventName = "koinos.contracts.token.transfer_event"
+	TokenMintEventName     = "koinos.contracts.token.mint_event"
+	TokenBurnEventName     = "koinos.contracts.token.burn_event"
+)
+
 func retrieveSymbol(ctx context.Context, client *cliutil.KoinosRPCClient, contractID []byte) (*string, error) {
 	symbolArguments := token.SymbolArguments{}
 
@@ -69,6 +77,45 @@ func retrieveDecimals(ctx context.Context, client *cliutil.KoinosRPCClient, cont
 	return &value, nil
 }
 
+func retrieveSymbolAndDecimals(ctx context.Context, client *cliutil.KoinosRPCClient, contractID []byte) (*string, *int, error) {
+	symbolArgs, err := proto.Marshal(&token.SymbolArguments{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decimalsArgs, err := proto.Marshal(&token.DecimalsArguments{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Both are read in a single batch
+	responses, errs := client.ReadContracts(ctx, []*chain.ReadContractRequest{
+		{ContractId: contractID, EntryPoint: TokenSymbolEntry, Args: symbolArgs},
+		{ContractId: contractID, EntryPoint: TokenDecimalsEntry, Args: decimalsArgs},
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	symbolResult := &token.SymbolResult{}
+	err = proto.Unmarshal(responses[0].GetResult(), symbolResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decimalsResult := &token.DecimalsResult{}
+	err = proto.Unmarshal(responses[1].GetResult(), decimalsResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := int(decimalsResult.Value)
+
+	return &symbolResult.Value, &value, nil
+}
+
 func retrieveBalance(ctx context.Context, client *cliutil.KoinosRPCClient, contractID []byte, address []byte) (*uint64, error) {
 	balanceOfArguments := token.BalanceOfArguments{}
 	balanceOfArguments.Owner = address
@@ -130,7 +177,13 @@ func (c *RegisterTokenCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 	}
 
 	var symbol *string
-	if c.Symbol == nil {
+	var precision *int
+	if c.Symbol == nil && c.Precision == nil {
+		symbol, precision, err = retrieveSymbolAndDecimals(ctx, ee.RPCClient, contractID)
+		if err != nil {
+			return nil, err
+		}
+	} else if c.Symbol == nil {
 		symbol, err = retrieveSymbol(ctx, ee.RPCClient, contractID)
 		if err != nil {
 			return nil, err
@@ -139,11 +192,12 @@ func (c *RegisterTokenCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 		symbol = c.Symbol
 	}
 
-	var precision *int
 	if c.Precision == nil {
-		precision, err = retrieveDecimals(ctx, ee.RPCClient, contractID)
-		if err != nil {
-			return nil, err
+		if precision == nil {
+			precision, err = retrieveDecimals(ctx, ee.RPCClient, contractID)
+			if err != nil {
+				return nil, err
+			}
 		}
 	} else {
 		precision = new(int)
@@ -153,32 +207,52 @@ func (c *RegisterTokenCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 		}
 	}
 
+	err = addTokenCommands(ee, c.Name, c.Address, contractID, *precision, *symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	er := NewExecutionResult()
+	er.AddMessage(fmt.Sprintf("Token '%s' at address %s registered", c.Name, c.Address))
+	return er, nil
+}
+
+// addTokenCommands registers the balance_of, total_supply and transfer commands of a token
+func addTokenCommands(ee *ExecutionEnvironment, name string, address string, contractID []byte, precision int, symbol string) error {
 	NewBalanceOfCommand := func(inv *CommandParseResult) Command {
-		return NewTokenBalanceCommand(inv, contractID, *precision, *symbol)
+		return NewTokenBalanceCommand(inv, contractID, precision, symbol)
 	}
-	cmd := NewCommandDeclaration(fmt.Sprintf("%s.balance_of", c.Name), "Checks the balance at an address", false, NewBalanceOfCommand, *NewOptionalCommandArg("address", AddressArg))
+	cmd := NewCommandDeclaration(fmt.Sprintf("%s.balance_of", name), "Checks the balance at an address", false, NewBalanceOfCommand, *NewOptionalCommandArg("address", AddressArg))
 	ee.Parser.Commands.AddCommand(cmd)
 
 	NewTotalSupplyCommand := func(inv *CommandParseResult) Command {
-		return NewTokenTotalSupplyCommand(inv, contractID, *precision, *symbol)
+		return NewTokenTotalSupplyCommand(inv, contractID, precision, symbol)
 	}
-	cmd = NewCommandDeclaration(fmt.Sprintf("%s.total_supply", c.Name), "Checks the token total supply", false, NewTotalSupplyCommand)
+	cmd = NewCommandDeclaration(fmt.Sprintf("%s.total_supply", name), "Checks the token total supply", false, NewTotalSupplyCommand)
 	ee.Parser.Commands.AddCommand(cmd)
 
 	NewTransferCommand := func(inv *CommandParseResult) Command {
-		return NewTokenTransferCommand(inv, contractID, *precision, *symbol)
+		return NewTokenTransferCommand(inv, contractID, precision, symbol)
 	}
-	cmd = NewCommandDeclaration(fmt.Sprintf("%s.transfer", c.Name), "Transfers the token", false, NewTransferCommand, *NewCommandArg("to", AddressArg), *NewCommandArg("amount", AmountArg))
+	cmd = NewCommandDeclaration(fmt.Sprintf("%s.transfer", name), "Transfers the token", false, NewTransferCommand, *NewCommandArg("to", AddressArg), *NewCommandArg("amount", AmountArg))
 	ee.Parser.Commands.AddCommand(cmd)
 
-	err = ee.Contracts.Add(c.Name, c.Address, nil, nil)
-	if err != nil {
-		return nil, err
+	if err := ee.Contracts.Add(name, address, nil, nil); err != nil {
+		return err
 	}
 
-	er := NewExecutionResult()
-	er.AddMessage(fmt.Sprintf("Token '%s' at address %s registered", c.Name, c.Address))
-	return er, nil
+	ee.Contracts[name].Token = &TokenInfo{Symbol: symbol, Precision: precision}
+
+	return nil
+}
+
+// removeTokenCommands unregisters a token and its commands
+func removeTokenCommands(ee *ExecutionEnvironment, name string) {
+	for _, method := range []string{"balance_of", "total_supply", "transfer"} {
+		ee.Parser.Commands.RemoveCommand(fmt.Sprintf("%s.%s", name, method))
+	}
+
+	delete(ee.Contracts, name)
 }
 
 // ----------------------------------------------------------------------------
@@ -204,6 +278,21 @@ func (c *TokenBalanceCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 		return nil, fmt.Errorf("%w: cannot check balance", cliutil.ErrOffline)
 	}
 
+	request, err := c.Request(ee)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ee.RPCClient.ReadContract(ctx, request.Args, request.ContractId, request.EntryPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Result(ee, resp)
+}
+
+// Request builds the balance_of request for the address
+func (c *TokenBalanceCommand) Request(ee *ExecutionEnvironment) (*chain.ReadContractRequest, error) {
 	var address []byte
 	if c.Address == nil {
 		if !ee.IsWalletOpen() {
@@ -218,18 +307,40 @@ func (c *TokenBalanceCommand) Execute(ctx context.Context, ee *ExecutionEnvironm
 		}
 	}
 
-	balance, err := retrieveBalance(ctx, ee.RPCClient, c.ContractID, address)
+	balanceOfArguments := token.BalanceOfArguments{}
+	balanceOfArguments.Owner = address
+
+	args, err := proto.Marshal(&balanceOfArguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chain.ReadContractRequest{ContractId: c.ContractID, EntryPoint: TokenBalanceOfEntry, Args: args}, nil
+}
+
+// Result decodes the balance_of response
+func (c *TokenBalanceCommand) Result(ee *ExecutionEnvironment, resp *chain.ReadContractResponse) (*ExecutionResult, error) {
+	balanceOfResult := &token.BalanceOfResult{}
+	err := proto.Unmarshal(resp.GetResult(), balanceOfResult)
 	if err != nil {
 		return nil, err
 	}
 
-	dec, err := util.SatoshiToDecimal(*balance, c.Precision)
+	dec, err := util.SatoshiToDecimal(balanceOfResult.Value, c.Precision)
 	if err != nil {
 		return nil, err
 	}
 
+	data := &AmountData{Amount: dec.String(), Value: balanceOfResult.Value, Symbol: c.Symbol}
+	if c.Address != nil {
+		data.Address = *c.Address
+	} else if ee.IsWalletOpen() {
+		data.Address = base58.Encode(ee.Key.AddressBytes())
+	}
+
 	er := NewExecutionResult()
 	er.AddMessage(fmt.Sprintf("%v %s", dec, c.Symbol))
+	er.SetData(data)
 
 	return er, nil
 }
@@ -281,6 +392,7 @@ func (c *TokenTotalSupplyCommand) Execute(ctx context.Context, ee *ExecutionEnvi
 
 	er := NewExecutionResult()
 	er.AddMessage(fmt.Sprintf("%v %s", dec, c.Symbol))
+	er.SetData(&AmountData{Amount: dec.String(), Value: totalSupplyResult.GetValue(), Symbol: c.Symbol})
 
 	return er, nil
 }
@@ -378,6 +490,7 @@ func (c *TokenTransferCommand) Execute(ctx context.Context, ee *ExecutionEnviron
 	err = ee.Session.AddOperation(op, fmt.Sprintf("Transfer %s %s to %s", decimalAmount, c.Symbol, c.Address))
 	if err == nil {
 		result.AddMessage("Adding operation to transaction session")
+		result.SetData(newSessionData(ee.Session))
 	}
 	if err != nil {
 		err := ee.SubmitTransaction(ctx, result, op)
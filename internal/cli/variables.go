@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-cli/internal/cliutil"
+)
+
+// Built-in variables, evaluated each time they are used
+const (
+	AddressVariable    = "address"
+	HeadHeightVariable = "head_height"
+	ChainIDVariable    = "chain_id"
+)
+
+// VariableReferenceTokens matches a reference to a variable, either $name or ${name}. Fields bound by let are referred
+// to with a dot, e.g. $id.transaction_id
+const VariableReferenceTokens = `\$(\{[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)*\}|[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)*)`
+
+var (
+	variableNameRE      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableReferenceRE = regexp.MustCompile(`\$\$|` + VariableReferenceTokens)
+)
+
+// secretArgs are the arguments never expanded, so that passwords and keys are used exactly as they are typed
+var secretArgs = map[string]bool{
+	"password":    true,
+	"private-key": true,
+}
+
+// blockArgs are the arguments holding commands, which are not expanded before their command runs, so that each of their
+// commands sees the current value of a for variable
+var blockArgs = map[string]bool{
+	"commands":      true,
+	"else-commands": true,
+}
+
+// isBuiltinVariable returns true if the name is a built-in variable, which cannot be set
+func isBuiltinVariable(name string) bool {
+	return name == AddressVariable || name == HeadHeightVariable || name == ChainIDVariable
+}
+
+// checkVariableName returns an error if the name cannot be set as a variable
+func checkVariableName(name string) error {
+	if !variableNameRE.MatchString(name) {
+		return fmt.Errorf("%w: invalid variable name %s", cliutil.ErrInvalidParam, name)
+	}
+
+	if isBuiltinVariable(name) {
+		return fmt.Errorf("%w: cannot set built-in variable %s", cliutil.ErrInvalidParam, name)
+	}
+
+	return nil
+}
+
+// SetVariable sets a variable, replacing any fields bound with it by let
+func (ee *ExecutionEnvironment) SetVariable(name string, value string) error {
+	if err := checkVariableName(name); err != nil {
+		return err
+	}
+
+	ee.UnsetVariable(name)
+	ee.variables[name] = value
+
+	return nil
+}
+
+// UnsetVariable removes a variable and any fields bound with it by let. Returns false if the variable was not set
+func (ee *ExecutionEnvironment) UnsetVariable(name string) bool {
+	_, ok := ee.variables[name]
+	delete(ee.variables, name)
+
+	for key := range ee.variables {
+		if strings.HasPrefix(key, name+".") {
+			delete(ee.variables, key)
+		}
+	}
+
+	return ok
+}
+
+// Variable returns the value of a variable, evaluating built-in variables
+func (ee *ExecutionEnvironment) Variable(ctx context.Context, name string) (string, error) {
+	if value, ok := ee.variables[name]; ok {
+		return value, nil
+	}
+
+	switch name {
+	case AddressVariable:
+		if !ee.IsWalletOpen() {
+			return "", fmt.Errorf("%w: cannot get $%s", cliutil.ErrWalletClosed, name)
+		}
+
+		return base58.Encode(ee.Key.AddressBytes()), nil
+	case HeadHeightVariable:
+		if !ee.IsOnline() {
+			return "", fmt.Errorf("%w: cannot get $%s", cliutil.ErrOffline, name)
+		}
+
+		headInfo, err := ee.RPCClient.GetHeadInfo(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(headInfo.GetHeadTopology().GetHeight(), 10), nil
+	case ChainIDVariable:
+		chainID, err := ee.GetChainID(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return base64.URLEncoding.EncodeToString(chainID), nil
+	}
+
+	return "", fmt.Errorf("%w: $%s", cliutil.ErrUnknownVariable, name)
+}
+
+// ExpandVariables replaces each $name or ${name} in a string with the value of the variable. $$ is a literal $
+func (ee *ExecutionEnvironment) ExpandVariables(ctx context.Context, s string) (string, error) {
+	var err error
+	expanded := variableReferenceRE.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" || err != nil {
+			return "$"
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(ref, "$"), "{"), "}")
+
+		var value string
+		value, err = ee.Variable(ctx, name)
+		return value
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
+
+// ExpandInvocation returns a copy of a parsed command with the variables in its arguments expanded. Expanded arguments
+// are checked against their type, since the parser only saw the variable. Arguments in single quotes are left as they
+// are, as are passwords and private keys. Command line arguments and blocks of commands are expanded when their
+// commands run
+func (ee *ExecutionEnvironment) ExpandInvocation(ctx context.Context, inv *CommandParseResult) (*CommandParseResult, error) {
+	if inv.Decl == nil {
+		return inv, nil
+	}
+
+	expanded := *inv
+	expanded.Args = make(map[string]*string, len(inv.Args))
+
+	for _, arg := range inv.Decl.Args {
+		value, ok := inv.Args[arg.Name]
+		if !ok {
+			continue
+		}
+
+		if value == nil || arg.ArgType == CommandLineArg || secretArgs[arg.Name] || blockArgs[arg.Name] || inv.literalArgs[arg.Name] {
+			expanded.Args[arg.Name] = value
+			continue
+		}
+
+		v, err := ee.ExpandVariables(ctx, *value)
+		if err != nil {
+			return nil, err
+		}
+
+		if v != *value && ee.Parser != nil {
+			switch arg.ArgType {
+			case StringArg, FileArg, CmdNameArg:
+			default:
+				if _, l, err := ee.Parser.parseArg(arg.ArgType, []byte(v)); err != nil || l != len(v) {
+					return nil, fmt.Errorf("%w: %s, %s is not a valid %s", cliutil.ErrInvalidParam, arg.Name, v, arg.ArgType.String())
+				}
+			}
+		}
+
+		expanded.Args[arg.Name] = &v
+	}
+
+	return &expanded, nil
+}
+
+// ExecuteInvocation expands the variables in the arguments of a parsed command and executes it
+func (ee *ExecutionEnvironment) ExecuteInvocation(ctx context.Context, inv *CommandParseResult) (*ExecutionResult, error) {
+	expanded, err := ee.ExpandInvocation(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	return expanded.Instantiate().Execute(ctx, ee)
+}
+
+// valuer is implemented by result payloads that have a main value, which let binds to the variable itself
+type valuer interface {
+	VariableValue() string
+}
+
+// resultValues returns the value let binds from a result, and the fields of its payload. The value is the payload's main
+// value, the only field of the payload, or the messages of the result
+func resultValues(result *ExecutionResult) (string, map[string]string) {
+	fields := make(map[string]string)
+
+	if result.Data != nil {
+		if b, err := json.Marshal(result.Data); err == nil {
+			object := make(map[string]interface{})
+			if json.Unmarshal(b, &object) == nil {
+				for key, value := range object {
+					fields[key] = fieldString(value)
+				}
+			}
+		}
+	}
+
+	if v, ok := result.Data.(valuer); ok {
+		return v.VariableValue(), fields
+	}
+
+	if len(fields) == 1 {
+		for _, value := range fields {
+			return value, fields
+		}
+	}
+
+	return strings.Join(result.Message, "\n"), fields
+}
+
+// fieldString formats a json value as a variable
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// ----------------------------------------------------------------------------
+// Set Command
+// ----------------------------------------------------------------------------
+
+// SetCommand is a command that sets or shows variables
+type SetCommand struct {
+	Name  *string
+	Value *string
+}
+
+// NewSetCommand creates a new set command object
+func NewSetCommand(inv *CommandParseResult) Command {
+	return &SetCommand{Name: inv.Args["name"], Value: inv.Args["value"]}
+}
+
+// Execute sets a variable, shows a variable, or lists the variables
+func (c *SetCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	result := NewExecutionResult()
+
+	if c.Name == nil {
+		names := make([]string, 0, len(ee.variables))
+		for name := range ee.variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			result.AddMessage(fmt.Sprintf("%s = %s", name, ee.variables[name]))
+		}
+
+		if len(names) == 0 {
+			result.AddMessage("No variables set")
+		}
+
+		data := make(map[string]string, len(ee.variables))
+		for name, value := range ee.variables {
+			data[name] = value
+		}
+		result.SetData(data)
+
+		return result, nil
+	}
+
+	if c.Value == nil {
+		value, err := ee.Variable(ctx, *c.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		result.AddMessage(fmt.Sprintf("%s = %s", *c.Name, value))
+		result.SetData(map[string]string{*c.Name: value})
+
+		return result, nil
+	}
+
+	if err := ee.SetVariable(*c.Name, *c.Value); err != nil {
+		return nil, err
+	}
+
+	result.AddMessage(fmt.Sprintf("%s = %s", *c.Name, *c.Value))
+	result.SetData(map[string]string{*c.Name: *c.Value})
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Unset Command
+// ----------------------------------------------------------------------------
+
+// UnsetCommand is a command that removes a variable
+type UnsetCommand struct {
+	Name string
+}
+
+// NewUnsetCommand creates a new unset command object
+func NewUnsetCommand(inv *CommandParseResult) Command {
+	return &UnsetCommand{Name: *inv.Args["name"]}
+}
+
+// Execute removes the variable
+func (c *UnsetCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	if !ee.UnsetVariable(c.Name) {
+		return nil, fmt.Errorf("%w: $%s", cliutil.ErrUnknownVariable, c.Name)
+	}
+
+	result := NewExecutionResult()
+	result.AddMessage(fmt.Sprintf("Unset %s", c.Name))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// Let Command
+// ----------------------------------------------------------------------------
+
+// LetCommand is a command that runs a command and binds its result to a variable
+type LetCommand struct {
+	Name    string
+	Command string
+}
+
+// NewLetCommand creates a new let command object
+func NewLetCommand(inv *CommandParseResult) Command {
+	return &LetCommand{Name: *inv.Args["name"], Command: *inv.Args["command"]}
+}
+
+// Execute runs the command, then binds the main value of its result to the variable, and each field of the result to
+// name.field
+func (c *LetCommand) Execute(ctx context.Context, ee *ExecutionEnvironment) (*ExecutionResult, error) {
+	// The name may run into the = when written without spaces, e.g. let id=...
+	line := c.Name + " " + c.Command
+	i := strings.Index(line, "=")
+	if i < 0 {
+		return nil, fmt.Errorf("%w: expected let <name> = <command>", cliutil.ErrInvalidParam)
+	}
+
+	name := strings.TrimSpace(line[:i])
+	if err := checkVariableName(name); err != nil {
+		return nil, err
+	}
+
+	parseResults, err := ee.Parser.Parse(line[i+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parseResults.CommandResults) != 1 {
+		return nil, fmt.Errorf("%w: let takes a single command", cliutil.ErrInvalidParam)
+	}
+
+	result, err := ee.ExecuteInvocation(ctx, parseResults.CommandResults[0])
+	if err != nil {
+		return result, err
+	}
+
+	value, fields := resultValues(result)
+	if err := ee.SetVariable(name, value); err != nil {
+		return nil, err
+	}
+
+	for field, v := range fields {
+		ee.variables[name+"."+field] = v
+	}
+
+	result.AddMessage(fmt.Sprintf("%s = %s", name, value))
+
+	return result, nil
+}
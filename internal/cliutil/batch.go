@@ -0,0 +1,202 @@
+package cliutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/chain"
+	jsonrpc "github.com/ybbus/jsonrpc/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// Batch settings
+const (
+	// MaxBatchSize is the maximum number of calls sent in a single JSON-RPC batch request
+	MaxBatchSize = 50
+
+	// MaxConcurrentCalls is the maximum number of requests made at the same time by a fan-out
+	MaxConcurrentCalls = 8
+)
+
+// BatchCall is a single call in a JSON-RPC batch. After the batch is made, either Response is filled or Err is set
+type BatchCall struct {
+	Method   string
+	Params   proto.Message
+	Response proto.Message
+	Err      error
+}
+
+// FanOut runs fn for each index from 0 to n-1 with at most limit running at the same time, and returns the error of each run.
+// Runs that have not started when the context is cancelled are skipped with the context's error
+func FanOut(ctx context.Context, n int, limit int, fn func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n)
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// CallBatch makes several idempotent rpc calls in a single JSON-RPC batch request, failing over between endpoints
+// like Call. If no endpoint accepts the batch, the calls are made individually, one after another, instead.
+// The error of each call is set on the call, the returned error is only for problems with the batch itself
+func (c *KoinosRPCClient) CallBatch(ctx context.Context, calls []*BatchCall) error {
+	if len(c.endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
+	if len(calls) == 0 {
+		return nil
+	}
+
+	reqs := make([]json.RawMessage, len(calls))
+	for i, call := range calls {
+		if !idempotentCalls[call.Method] {
+			return fmt.Errorf("%w: %s cannot be batched", ErrInvalidParam, call.Method)
+		}
+
+		req, err := kjson.Marshal(call.Params)
+		if err != nil {
+			return err
+		}
+		reqs[i] = req
+	}
+
+	c.startHealthCheck()
+
+	for _, endpoint := range c.candidates() {
+		err := c.batchEndpoint(ctx, endpoint, calls, reqs)
+		if err == nil {
+			c.markSucceeded(endpoint)
+			return nil
+		}
+
+		// The endpoint is not marked as failed, as it may just not support batches. The individual calls below
+		// fail over if it is really unreachable
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+
+	// The calls are not fanned out, as batches are already sent concurrently by ReadContracts and the requests would
+	// multiply past MaxConcurrentCalls
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			call.Err = err
+			continue
+		}
+
+		call.Err = c.Call(ctx, call.Method, call.Params, call.Response)
+	}
+
+	return nil
+}
+
+// batchEndpoint makes a batch request to a single endpoint, giving up after the call timeout
+func (c *KoinosRPCClient) batchEndpoint(ctx context.Context, endpoint *RPCEndpoint, calls []*BatchCall, reqs []json.RawMessage) error {
+	transport, ok := endpoint.client.(*jsonRPCTransport)
+	if !ok {
+		return fmt.Errorf("%s does not support batch requests", endpoint.URL)
+	}
+
+	options := c.Options()
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	requests := make(jsonrpc.RPCRequests, len(calls))
+	for i, call := range calls {
+		requests[i] = jsonrpc.NewRequest(call.Method, reqs[i])
+	}
+
+	start := time.Now()
+	responses, err := transport.client.CallBatch(ctx, requests)
+	if err != nil {
+		return err
+	}
+
+	// The batch request assigns each call its index as id
+	byID := responses.AsMap()
+	if len(byID) != len(calls) {
+		return fmt.Errorf("%w: expected %d responses in batch, got %d", ErrInvalidResponse, len(calls), len(byID))
+	}
+
+	for i := range calls {
+		if byID[i] == nil {
+			return fmt.Errorf("%w: batch response is missing id %d", ErrInvalidResponse, i)
+		}
+	}
+
+	for i, call := range calls {
+		call.Err = decodeResponse(byID[i], call.Response)
+	}
+
+	latency := time.Since(start)
+	if options.Tracer != nil && !isUntraced(ctx) {
+		for _, call := range calls {
+			options.Tracer.Trace(endpoint.URL, call.Method, call.Params, call.Response, latency, call.Err)
+		}
+	}
+
+	return nil
+}
+
+// ReadContracts reads from several contracts using batch requests. It returns the response or error of each request
+func (c *KoinosRPCClient) ReadContracts(ctx context.Context, requests []*chain.ReadContractRequest) ([]*chain.ReadContractResponse, []error) {
+	calls := make([]*BatchCall, len(requests))
+	for i, request := range requests {
+		calls[i] = &BatchCall{Method: ReadContractCall, Params: request, Response: &chain.ReadContractResponse{}}
+	}
+
+	// Large reads are split into several batches which are sent concurrently
+	numBatches := (len(calls) + MaxBatchSize - 1) / MaxBatchSize
+	batchErrs := FanOut(ctx, numBatches, MaxConcurrentCalls, func(ctx context.Context, i int) error {
+		end := (i + 1) * MaxBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		return c.CallBatch(ctx, calls[i*MaxBatchSize:end])
+	})
+
+	responses := make([]*chain.ReadContractResponse, len(calls))
+	errs := make([]error, len(calls))
+	for i, call := range calls {
+		errs[i] = call.Err
+		if batchErr := batchErrs[i/MaxBatchSize]; batchErr != nil {
+			errs[i] = batchErr
+		}
+
+		if errs[i] == nil {
+			responses[i] = call.Response.(*chain.ReadContractResponse)
+		}
+	}
+
+	return responses, errs
+}
@@ -17,6 +17,12 @@ var (
 	// ErrMissingParam is returned when a parameter is missing.
 	ErrMissingParam = errors.New("missing parameter")
 
+	// ErrUnknownParam is returned when a parameter is given by a name the command does not have.
+	ErrUnknownParam = errors.New("unknown parameter")
+
+	// ErrDuplicateParam is returned when a parameter is given more than once.
+	ErrDuplicateParam = errors.New("parameter given more than once")
+
 	// ErrInvalidParam is returned when a parameter is invalid.
 	ErrInvalidParam = errors.New("invalid value given for parameter")
 
@@ -62,6 +68,45 @@ var (
 	// ErrContract is returned when a contract is already registered
 	ErrContract = errors.New("contract error")
 
+	// ErrInvalidTransaction is returned when a transaction cannot be decoded
+	ErrInvalidTransaction = errors.New("invalid transaction")
+
+	// ErrUnknownEventType is returned when the type of an event cannot be found
+	ErrUnknownEventType = errors.New("unknown event type")
+
+	// ErrBlockNotFound is returned when a requested block does not exist
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrTransactionNotFound is returned when a requested transaction does not exist
+	ErrTransactionNotFound = errors.New("transaction not found")
+
+	// ErrNoEndpoints is returned when an rpc client is created without any endpoints
+	ErrNoEndpoints = errors.New("no rpc endpoints")
+
+	// ErrChainIDMismatch is returned when an endpoint reports a different chain id than expected
+	ErrChainIDMismatch = errors.New("chain id mismatch")
+
+	// ErrEndpointBehind is returned when an endpoint's head block is too far behind the other endpoints
+	ErrEndpointBehind = errors.New("endpoint is behind")
+
+	// ErrSubmissionUncertain is returned when a transaction submission failed in a way that it may still have been accepted
+	ErrSubmissionUncertain = errors.New("transaction submission status unknown")
+
+	// ErrCommandCancelled is returned when the user cancels a running command
+	ErrCommandCancelled = errors.New("command cancelled")
+
+	// ErrNotKoinosNode is returned when an endpoint responds, but not as a koinos node
+	ErrNotKoinosNode = errors.New("not a koinos node")
+
 	// ErrInsufficientRC is returned when not enough resource credits can be used to cover a transaction
 	ErrInsufficientRC = errors.New("insufficient rc")
+
+	// ErrUnknownVariable is returned when a command refers to a variable that is not set
+	ErrUnknownVariable = errors.New("unknown variable")
+
+	// ErrTransactionReverted is returned when a transaction was included in a block, but its operations were reverted
+	ErrTransactionReverted = errors.New("transaction reverted")
+
+	// ErrAssertionFailed is returned when a script check, such as assert or expect_balance, does not hold
+	ErrAssertionFailed = errors.New("assertion failed")
 )
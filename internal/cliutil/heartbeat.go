@@ -0,0 +1,207 @@
+package cliutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Node heartbeat settings
+const (
+	// HeartbeatInterval is how often the background heartbeat checks the node
+	HeartbeatInterval = 15 * time.Second
+
+	// RecheckInterval is how long an unreachable node is reported as unreachable before it is checked again when needed,
+	// while no heartbeat runs
+	RecheckInterval = time.Second
+
+	// StaleHeadAge is how far the node's head block time can be behind wall-clock time before the node is considered stale
+	StaleHeadAge = time.Minute
+)
+
+// NodeStatus is the reachability of a node as seen by the last handshake or heartbeat
+type NodeStatus int
+
+// Node statuses
+const (
+	NodeUnknown NodeStatus = iota
+	NodeOnline
+	NodeDegraded
+	NodeStale
+	NodeUnreachable
+)
+
+// String returns the name of the status
+func (s NodeStatus) String() string {
+	switch s {
+	case NodeOnline:
+		return "online"
+	case NodeDegraded:
+		return "degraded"
+	case NodeStale:
+		return "stale"
+	case NodeUnreachable:
+		return "unreachable"
+	default:
+		return "unknown"
+	}
+}
+
+// NodeHealth is the result of a handshake with a node
+type NodeHealth struct {
+	Status     NodeStatus
+	ChainID    []byte
+	HeadHeight uint64
+	HeadTime   time.Time
+	Err        error
+	Checked    time.Time
+}
+
+// NodeMonitor checks that a node is reachable and keeping up with the chain, optionally in the background
+type NodeMonitor struct {
+	client *KoinosRPCClient
+	health NodeHealth
+	cancel context.CancelFunc
+	done   chan struct{}
+	mutex  sync.Mutex
+}
+
+// NewNodeMonitor creates a monitor for the client's node. The status is unknown until the first check
+func NewNodeMonitor(client *KoinosRPCClient) *NodeMonitor {
+	return &NodeMonitor{client: client}
+}
+
+// Check makes a handshake with the node, getting its chain id and head block. It returns an error when the node
+// responds but is not a koinos node. An unreachable node is not an error, it is reported in the health status
+func (m *NodeMonitor) Check(ctx context.Context) (NodeHealth, error) {
+	health := NodeHealth{Checked: time.Now()}
+
+	// With several endpoints, the handshake also finds which of them are healthy
+	if len(m.client.Endpoints()) > 1 {
+		m.client.CheckHealth(ctx)
+	}
+
+	chainID, err := m.client.GetChainID(ctx)
+	if err == nil {
+		health.ChainID = chainID
+
+		headInfo, headErr := m.client.GetHeadInfo(ctx)
+		if headErr == nil {
+			health.HeadHeight = headInfo.GetHeadTopology().GetHeight()
+			health.HeadTime = TimestampToTime(headInfo.HeadBlockTime)
+		}
+		err = headErr
+	}
+
+	// A cancelled check says nothing about the node
+	if ctx.Err() != nil {
+		return m.Health(), ctx.Err()
+	}
+
+	var rpcErr KoinosRPCError
+	switch {
+	case errors.As(err, &rpcErr) || errors.Is(err, ErrInvalidResponse):
+		err = fmt.Errorf("%w: %s, %v", ErrNotKoinosNode, m.client.CurrentURL(), err)
+		health.Status = NodeUnreachable
+		health.Err = err
+	case err != nil:
+		health.Status = NodeUnreachable
+		health.Err = err
+		err = nil
+	case health.Checked.Sub(health.HeadTime) > StaleHeadAge:
+		health.Status = NodeStale
+	case m.hasUnhealthyEndpoint():
+		health.Status = NodeDegraded
+	default:
+		health.Status = NodeOnline
+	}
+
+	m.mutex.Lock()
+	m.health = health
+	m.mutex.Unlock()
+
+	return health, err
+}
+
+// hasUnhealthyEndpoint returns true when any endpoint failed its last health check
+func (m *NodeMonitor) hasUnhealthyEndpoint() bool {
+	for _, endpoint := range m.client.Endpoints() {
+		if !endpoint.Healthy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Recheck checks the node again when its last check found it unreachable and is older than the interval, and returns
+// its health
+func (m *NodeMonitor) Recheck(ctx context.Context, interval time.Duration) NodeHealth {
+	health := m.Health()
+	if health.Status != NodeUnreachable || time.Since(health.Checked) < interval {
+		return health
+	}
+
+	health, _ = m.Check(ctx)
+	return health
+}
+
+// Health returns the result of the last check
+func (m *NodeMonitor) Health() NodeHealth {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.health
+}
+
+// Start checks the node in the background at the given interval until Stop is called
+func (m *NodeMonitor) Start(interval time.Duration) {
+	m.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	m.mutex.Lock()
+	m.cancel = cancel
+	m.done = done
+	m.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.Check(WithoutTrace(ctx))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Running returns true if the node is being checked in the background
+func (m *NodeMonitor) Running() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.cancel != nil
+}
+
+// Stop stops the background checks, waiting for a running check to finish
+func (m *NodeMonitor) Stop() {
+	m.mutex.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,35 @@
+package cliutil
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ManaRegenerationTime is the time mana takes to regenerate from zero to the maximum, which is the account's KOIN balance
+const ManaRegenerationTime = 5 * 24 * time.Hour
+
+// ManaInfo is the current and maximum mana of an account, in satoshi
+type ManaInfo struct {
+	Current uint64
+	Max     uint64
+}
+
+// RegenerationPerDay returns the mana regenerated each day, in satoshi
+func (m *ManaInfo) RegenerationPerDay() uint64 {
+	return uint64(float64(m.Max) * float64(24*time.Hour) / float64(ManaRegenerationTime))
+}
+
+// TimeUntil returns the estimated time until the given amount of mana is available. Mana beyond the maximum is never available
+func (m *ManaInfo) TimeUntil(amount uint64) (time.Duration, error) {
+	if amount <= m.Current {
+		return 0, nil
+	}
+
+	if amount > m.Max {
+		return 0, fmt.Errorf("%w: more than the maximum mana", ErrInvalidAmount)
+	}
+
+	d := float64(amount-m.Current) / float64(m.Max) * float64(ManaRegenerationTime)
+	return time.Duration(math.Ceil(d/float64(time.Second))) * time.Second, nil
+}
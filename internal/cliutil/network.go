@@ -0,0 +1,124 @@
+package cliutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+// Built in network profile names
+const (
+	MainnetNetwork = "mainnet"
+	TestnetNetwork = "testnet"
+	LocalNetwork   = "local"
+)
+
+// NetworkToken is a well-known token contract of a network. When the symbol is empty, the symbol and precision are read from the contract
+type NetworkToken struct {
+	Address   string
+	Symbol    string
+	Precision int
+}
+
+// NetworkProfile bundles the rpc endpoints, expected chain id and well-known contracts of a network
+type NetworkProfile struct {
+	Name        string
+	DisplayName string
+	Endpoints   []EndpointConfig
+
+	// ChainID is the base64 chain id that transactions on this network must be signed for. It is not pinned when empty
+	ChainID string
+
+	Tokens map[string]NetworkToken
+}
+
+// DefaultNetworkProfiles returns the built in network profiles
+func DefaultNetworkProfiles() map[string]*NetworkProfile {
+	return map[string]*NetworkProfile{
+		MainnetNetwork: {
+			Name:        MainnetNetwork,
+			DisplayName: "Koinos Mainnet",
+			Endpoints:   []EndpointConfig{{URL: "https://api.koinos.io"}},
+			ChainID:     "EiBZK_GGVP0H_fXVAM3j6EAuz3-B-l3ejxRSewi7qIBfSA==",
+			Tokens: map[string]NetworkToken{
+				"koin": {Address: KoinContractID, Symbol: KoinSymbol, Precision: KoinPrecision},
+				"vhp":  {Address: "18tWNU7E4yuQzz7hMVpceb9ixmaWLVyQsr", Symbol: "VHP", Precision: 8},
+			},
+		},
+		TestnetNetwork: {
+			Name:        TestnetNetwork,
+			DisplayName: "Koinos Testnet (Harbinger)",
+			Endpoints:   []EndpointConfig{{URL: "https://harbinger-api.koinos.io"}},
+			ChainID:     "EiBncD4pKRIQWco_WRqo5Q-xnXR7JuO3PtZv983mKdKHSQ==",
+			Tokens: map[string]NetworkToken{
+				"koin": {Address: "1FaSvLjQJsCJKq5ybmGsMMQs8RQYyVv8ju"},
+				"vhp":  {Address: "17n12ktwN79sR6ia9DDgCfmw77EgpbTyBi"},
+			},
+		},
+		LocalNetwork: {
+			Name:        LocalNetwork,
+			DisplayName: "Local Node",
+			Endpoints:   []EndpointConfig{{URL: "http://localhost:8080"}},
+		},
+	}
+}
+
+// NewCustomNetworkProfile creates a profile for a network that is not built in. The chain id may be empty to not pin it
+func NewCustomNetworkProfile(name string, endpoints []EndpointConfig, chainID string) (*NetworkProfile, error) {
+	if chainID != "" {
+		if _, err := base64.URLEncoding.DecodeString(chainID); err != nil {
+			return nil, fmt.Errorf("%w: chain id must be a base64 string", ErrInvalidParam)
+		}
+	}
+
+	return &NetworkProfile{Name: name, DisplayName: name, Endpoints: endpoints, ChainID: chainID}, nil
+}
+
+// IsPinned returns true if the profile expects a chain id
+func (p *NetworkProfile) IsPinned() bool {
+	return p.ChainID != ""
+}
+
+// PinnedChainID returns the chain id pinned by the profile, or nil when it is not pinned
+func (p *NetworkProfile) PinnedChainID() []byte {
+	if !p.IsPinned() {
+		return nil
+	}
+
+	chainID, err := base64.URLEncoding.DecodeString(p.ChainID)
+	if err != nil {
+		return nil
+	}
+
+	return chainID
+}
+
+// CheckChainID returns an error if the chain id is not the one pinned by the profile
+func (p *NetworkProfile) CheckChainID(chainID []byte) error {
+	if !p.IsPinned() {
+		return nil
+	}
+
+	pinned, err := base64.URLEncoding.DecodeString(p.ChainID)
+	if err != nil {
+		return fmt.Errorf("%w: pinned chain id of %s is not a base64 string", ErrInvalidParam, p.Name)
+	}
+
+	if !bytes.Equal(pinned, chainID) {
+		return fmt.Errorf("%w: %s expects %s, got %s", ErrChainIDMismatch, p.DisplayName, p.ChainID, base64.URLEncoding.EncodeToString(chainID))
+	}
+
+	return nil
+}
+
+// TokenNames returns the names of the profile's well-known tokens in alphabetical order
+func (p *NetworkProfile) TokenNames() []string {
+	names := make([]string, 0, len(p.Tokens))
+	for name := range p.Tokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,143 @@
+package cliutil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
+	koinos_chain "github.com/koinos/koinos-proto-golang/v2/koinos/chain"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+// Resource estimates for operations whose actual usage is only known once they are applied by a node
+const (
+	// SignatureSize is the size of a compact transaction signature
+	SignatureSize = 65
+
+	// EstimatedTransactionCompute is the compute bandwidth of applying any transaction, verifying its signature, nonce and payer
+	EstimatedTransactionCompute = 100000
+
+	// EstimatedCallCompute is the compute bandwidth of a typical contract call, such as a token transfer
+	EstimatedCallCompute = 300000
+
+	// EstimatedCallDisk is the disk storage of a typical contract call, such as a token balance created by a transfer
+	EstimatedCallDisk = 100
+
+	// EstimatedUploadComputePerByte is the compute bandwidth of uploading each byte of contract bytecode
+	EstimatedUploadComputePerByte = 10
+
+	// EstimatedSystemOpCompute is the compute bandwidth of setting a system call or system contract
+	EstimatedSystemOpCompute = 50000
+)
+
+// ResourceEstimate is the resources and mana a transaction is expected to use
+type ResourceEstimate struct {
+	NetworkBandwidth uint64 `json:"network_bandwidth"`
+	DiskStorage      uint64 `json:"disk_storage"`
+	ComputeBandwidth uint64 `json:"compute_bandwidth"`
+
+	NetworkMana uint64 `json:"network_mana"`
+	DiskMana    uint64 `json:"disk_mana"`
+	ComputeMana uint64 `json:"compute_mana"`
+}
+
+// Mana returns the total mana of the estimate, in satoshi
+func (e *ResourceEstimate) Mana() uint64 {
+	return e.NetworkMana + e.DiskMana + e.ComputeMana
+}
+
+// EstimateTransactionResources estimates the resources a transaction will use and their cost under the given limits. The
+// network bandwidth is the serialized size of the transaction, counting the signatures it will have once signed. Disk
+// storage and compute bandwidth are estimated from the kind of each operation
+func EstimateTransactionResources(transaction *protocol.Transaction, signatures int, limits *koinos_chain.ResourceLimitData) *ResourceEstimate {
+	signed := proto.Clone(transaction).(*protocol.Transaction)
+	for len(signed.Signatures) < signatures {
+		signed.Signatures = append(signed.Signatures, make([]byte, SignatureSize))
+	}
+
+	estimate := &ResourceEstimate{
+		NetworkBandwidth: uint64(proto.Size(signed)),
+		ComputeBandwidth: EstimatedTransactionCompute,
+	}
+
+	for _, op := range transaction.Operations {
+		switch o := op.Op.(type) {
+		case *protocol.Operation_UploadContract:
+			size := uint64(len(o.UploadContract.Bytecode) + len(o.UploadContract.Abi))
+			estimate.DiskStorage += size
+			estimate.ComputeBandwidth += size * EstimatedUploadComputePerByte
+		case *protocol.Operation_CallContract:
+			estimate.DiskStorage += EstimatedCallDisk
+			estimate.ComputeBandwidth += EstimatedCallCompute
+		default:
+			estimate.ComputeBandwidth += EstimatedSystemOpCompute
+		}
+	}
+
+	estimate.NetworkMana = estimate.NetworkBandwidth * limits.NetworkBandwidthCost
+	estimate.DiskMana = estimate.DiskStorage * limits.DiskStorageCost
+	estimate.ComputeMana = estimate.ComputeBandwidth * limits.ComputeBandwidthCost
+
+	return estimate
+}
+
+// CachedResourceLimits are the resource limits of a chain, kept to estimate costs while offline
+type CachedResourceLimits struct {
+	Limits  *koinos_chain.ResourceLimitData
+	ChainID []byte
+	Fetched time.Time
+}
+
+type cachedResourceLimitsFile struct {
+	Limits  json.RawMessage `json:"limits"`
+	ChainID string          `json:"chain_id"`
+	Fetched time.Time       `json:"fetched"`
+}
+
+// SaveResourceLimits writes cached resource limits to a file
+func SaveResourceLimits(filename string, cached *CachedResourceLimits) error {
+	limits, err := kjson.Marshal(cached.Limits)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&cachedResourceLimitsFile{
+		Limits:  limits,
+		ChainID: base64.URLEncoding.EncodeToString(cached.ChainID),
+		Fetched: cached.Fetched,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+// LoadResourceLimits reads cached resource limits from a file written by SaveResourceLimits
+func LoadResourceLimits(filename string) (*CachedResourceLimits, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file cachedResourceLimitsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: resource limits cache %s: %v", ErrInvalidParam, filename, err)
+	}
+
+	limits := &koinos_chain.ResourceLimitData{}
+	if err := kjson.Unmarshal(file.Limits, limits); err != nil {
+		return nil, fmt.Errorf("%w: resource limits cache %s: %v", ErrInvalidParam, filename, err)
+	}
+
+	chainID, err := base64.URLEncoding.DecodeString(file.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: resource limits cache %s: %v", ErrInvalidParam, filename, err)
+	}
+
+	return &CachedResourceLimits{Limits: limits, ChainID: chainID, Fetched: file.Fetched}, nil
+}
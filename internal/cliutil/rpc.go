@@ -1,30 +1,56 @@
 package cliutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
+	koinos_chain "github.com/koinos/koinos-proto-golang/v2/koinos/chain"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/contract_meta_store"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/contracts/token"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	account_history_rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/account_history"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/block_store"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/chain"
 	contract_meta_store_rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/contract_meta_store"
+	_ "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/koindx_tracker" // Registers the rpc types for RPCMessageTypes
 	"github.com/koinos/koinos-proto-golang/v2/koinos/rpc/mempool"
+	_ "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/p2p" // Registers the rpc types for RPCMessageTypes
+	transaction_store_rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/transaction_store"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
 	util "github.com/koinos/koinos-util-golang/v2"
 	jsonrpc "github.com/ybbus/jsonrpc/v3"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // These are the rpc calls that the wallet uses
 const (
-	ReadContractCall      = "chain.read_contract"
-	GetAccountNonceCall   = "chain.get_account_nonce"
-	GetAccountRcCall      = "chain.get_account_rc"
-	SubmitTransactionCall = "chain.submit_transaction"
-	GetChainIDCall        = "chain.get_chain_id"
-	GetContractMetaCall   = "contract_meta_store.get_contract_meta"
-	GetPendingNonceCall   = "mempool.get_pending_nonce"
+	ReadContractCall           = "chain.read_contract"
+	GetAccountNonceCall        = "chain.get_account_nonce"
+	GetResourceLimitsCall      = "chain.get_resource_limits"
+	GetAccountRcCall           = "chain.get_account_rc"
+	SubmitTransactionCall      = "chain.submit_transaction"
+	GetChainIDCall             = "chain.get_chain_id"
+	GetContractMetaCall        = "contract_meta_store.get_contract_meta"
+	GetPendingNonceCall        = "mempool.get_pending_nonce"
+	GetPendingTransactionsCall = "mempool.get_pending_transactions"
+	GetHeadInfoCall            = "chain.get_head_info"
+	GetForkHeadsCall           = "chain.get_fork_heads"
+	GetBlocksByIDCall          = "block_store.get_blocks_by_id"
+	GetBlocksByHeightCall      = "block_store.get_blocks_by_height"
+	GetTransactionsByIDCall    = "transaction_store.get_transactions_by_id"
+	GetAccountHistoryCall      = "account_history.get_account_history"
 )
 
 // SubmissionParams is the parameters for a transaction submission
@@ -34,72 +60,609 @@ type SubmissionParams struct {
 	ChainID []byte
 }
 
-// KoinosRPCError is a golang error that also contains log messages from a reverted transaction
+// KoinosRPCError is a golang error that also contains the chain error code and log messages from a reverted transaction
 type KoinosRPCError struct {
+	Code    koinos_chain.ErrorCode
+	Message string
 	Logs    []string
-	message string
 }
 
 // Error returns the error message
 func (e KoinosRPCError) Error() string {
-	return e.message
+	return e.Message
 }
 
-// KoinosRPCClient is a wrapper around the jsonrpc client
-type KoinosRPCClient struct {
+// Is allows matching the error code of an rpc error against the related cliutil errors
+func (e KoinosRPCError) Is(target error) bool {
+	return target == ErrInsufficientRC && e.Code == koinos_chain.ErrorCode_insufficient_rc
+}
+
+// NewKoinosRPCError creates a KoinosRPCError from the message and data of a JSON-RPC error
+func NewKoinosRPCError(message string, data interface{}) KoinosRPCError {
+	err := KoinosRPCError{Message: message}
+
+	var dataBytes []byte
+	switch d := data.(type) {
+	case nil:
+		return err
+	case string:
+		dataBytes = []byte(d)
+	default:
+		b, e := json.Marshal(d)
+		if e != nil {
+			return err
+		}
+		dataBytes = b
+	}
+
+	details := &koinos_chain.ErrorDetails{}
+	if e := kjson.Unmarshal(dataBytes, details); e == nil {
+		err.Code = koinos_chain.ErrorCode(details.Code)
+		err.Logs = details.Logs
+	}
+
+	return err
+}
+
+// Endpoint health check settings
+const (
+	// HealthCheckInterval is how often the health of the endpoints is checked when routing calls
+	HealthCheckInterval = 30 * time.Second
+
+	// MaxHeadHeightLag is how many blocks an endpoint can be behind the highest endpoint before it is considered unhealthy
+	MaxHeadHeightLag = 20
+)
+
+// Default rpc call settings
+const (
+	// DefaultRPCTimeout is how long a single rpc call may take before it is abandoned
+	DefaultRPCTimeout = 30 * time.Second
+
+	// DefaultRPCRetries is how many times an idempotent read is retried when no endpoint can be reached
+	DefaultRPCRetries = 3
+
+	// DefaultRPCRetryDelay is the delay before the first retry, doubling with each further retry
+	DefaultRPCRetryDelay = 500 * time.Millisecond
+)
+
+// idempotentCalls are the rpc calls that can safely be retried
+var idempotentCalls = map[string]bool{
+	ReadContractCall:           true,
+	GetAccountNonceCall:        true,
+	GetResourceLimitsCall:      true,
+	GetAccountRcCall:           true,
+	GetChainIDCall:             true,
+	GetContractMetaCall:        true,
+	GetPendingNonceCall:        true,
+	GetPendingTransactionsCall: true,
+	GetHeadInfoCall:            true,
+	GetForkHeadsCall:           true,
+	GetBlocksByIDCall:          true,
+	GetBlocksByHeightCall:      true,
+	GetTransactionsByIDCall:    true,
+	GetAccountHistoryCall:      true,
+}
+
+// RPCOptions are the timeout, retry and tracing settings used for every rpc call
+type RPCOptions struct {
+	Timeout    time.Duration
+	Retries    int
+	RetryDelay time.Duration
+	Tracer     *RPCTracer
+}
+
+// DefaultRPCOptions returns the default rpc call settings
+func DefaultRPCOptions() RPCOptions {
+	return RPCOptions{Timeout: DefaultRPCTimeout, Retries: DefaultRPCRetries, RetryDelay: DefaultRPCRetryDelay}
+}
+
+// RPCEndpoint is a single node that the rpc client can route calls to, along with the result of its last health check
+type RPCEndpoint struct {
+	URL         string
+	Healthy     bool
+	HeadHeight  uint64
+	ChainID     []byte
+	LastError   error
+	LastChecked time.Time
+
+	client rpcTransport
+}
+
+// rpcTransport sends calls to a single endpoint
+type rpcTransport interface {
+	Call(ctx context.Context, method string, params proto.Message, req json.RawMessage, returnType proto.Message) error
+	Close() error
+}
+
+// jsonRPCTransport sends calls to a JSON-RPC endpoint
+type jsonRPCTransport struct {
 	client jsonrpc.RPCClient
 }
 
-// NewKoinosRPCClient creates a new koinos rpc client
-func NewKoinosRPCClient(url string) *KoinosRPCClient {
-	client := jsonrpc.NewClient(url)
-	return &KoinosRPCClient{client: client}
+// Call makes the json rpc call and decodes the response
+func (t *jsonRPCTransport) Call(ctx context.Context, method string, params proto.Message, req json.RawMessage, returnType proto.Message) error {
+	return doCall(ctx, t.client, method, req, returnType)
+}
+
+// Close does nothing, as JSON-RPC endpoints do not hold a connection
+func (t *jsonRPCTransport) Close() error {
+	return nil
+}
+
+// KoinosRPCClient is a wrapper around one or more jsonrpc clients which routes calls to a healthy endpoint
+type KoinosRPCClient struct {
+	endpoints   []*RPCEndpoint
+	current     int
+	lastCheck   time.Time
+	options     RPCOptions
+	chainID     []byte
+	cancelCheck context.CancelFunc
+	checks      sync.WaitGroup
+	mutex       sync.Mutex
+}
+
+// NewKoinosRPCClient creates a new koinos rpc client. Calls go to the first endpoint, failing over to the others when it is unreachable
+func NewKoinosRPCClient(urls ...string) *KoinosRPCClient {
+	configs := make([]EndpointConfig, len(urls))
+	for i, url := range urls {
+		configs[i] = EndpointConfig{URL: url}
+	}
+
+	// Endpoints configured with only a url cannot fail
+	c, _ := NewKoinosRPCClientWithConfig(configs...)
+	return c
+}
+
+// NewKoinosRPCClientWithConfig creates a new koinos rpc client with headers, proxies and tls set per endpoint
+func NewKoinosRPCClientWithConfig(configs ...EndpointConfig) (*KoinosRPCClient, error) {
+	c := &KoinosRPCClient{options: DefaultRPCOptions()}
+	for _, config := range configs {
+		client, err := newEndpointClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", config.URL, err)
+		}
+
+		c.endpoints = append(c.endpoints, &RPCEndpoint{URL: config.URL, Healthy: true, client: client})
+	}
+
+	return c, nil
+}
+
+// ParseEndpoints splits a comma or space separated list of endpoint urls
+func ParseEndpoints(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// SetOptions sets the timeout, retry and tracing settings for further calls
+func (c *KoinosRPCClient) SetOptions(options RPCOptions) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.options = options
+}
+
+// SetChainID pins the chain id that endpoints must report to be healthy. A nil chain id leaves it to the endpoints
+func (c *KoinosRPCClient) SetChainID(chainID []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.chainID = chainID
+}
+
+// Options returns the timeout, retry and tracing settings
+func (c *KoinosRPCClient) Options() RPCOptions {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.options
+}
+
+// URLs returns the urls of all endpoints
+func (c *KoinosRPCClient) URLs() []string {
+	urls := make([]string, len(c.endpoints))
+	for i, endpoint := range c.endpoints {
+		urls[i] = endpoint.URL
+	}
+
+	return urls
+}
+
+// Endpoints returns a snapshot of the endpoints and their health
+func (c *KoinosRPCClient) Endpoints() []RPCEndpoint {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	endpoints := make([]RPCEndpoint, len(c.endpoints))
+	for i, endpoint := range c.endpoints {
+		endpoints[i] = *endpoint
+	}
+
+	return endpoints
+}
+
+// CurrentURL returns the url of the endpoint that calls are currently routed to
+func (c *KoinosRPCClient) CurrentURL() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.endpoints[c.current].URL
+}
+
+// Close closes the connections held by the endpoints, stopping a running background health check first
+func (c *KoinosRPCClient) Close() error {
+	c.mutex.Lock()
+	if c.cancelCheck != nil {
+		c.cancelCheck()
+	}
+	c.mutex.Unlock()
+	c.checks.Wait()
+
+	var firstErr error
+	for _, endpoint := range c.endpoints {
+		if err := endpoint.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
 }
 
-// Call wraps the rpc client call and handles some of the boilerplate
+// CheckHealth queries the head info and chain id of every endpoint. An endpoint is healthy when it responds, reports
+// the pinned chain id, or the chain id of most responding endpoints when none is pinned, and is not too far behind the
+// highest endpoint
+func (c *KoinosRPCClient) CheckHealth(ctx context.Context) []RPCEndpoint {
+	type healthResult struct {
+		height  uint64
+		chainID []byte
+		err     error
+	}
+
+	emptyParams := json.RawMessage("{}")
+	results := make([]healthResult, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range c.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *RPCEndpoint) {
+			defer wg.Done()
+
+			var headInfo chain.GetHeadInfoResponse
+			if err := c.callEndpoint(ctx, endpoint, GetHeadInfoCall, &chain.GetHeadInfoRequest{}, emptyParams, &headInfo); err != nil {
+				results[i].err = err
+				return
+			}
+
+			var chainID chain.GetChainIdResponse
+			if err := c.callEndpoint(ctx, endpoint, GetChainIDCall, &chain.GetChainIdRequest{}, emptyParams, &chainID); err != nil {
+				results[i].err = err
+				return
+			}
+
+			results[i].height = headInfo.GetHeadTopology().GetHeight()
+			results[i].chainID = chainID.ChainId
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The reference chain id is the pinned one, or else the one most endpoints report, the first in order on a tie. All
+	// endpoints on it are compared to the highest head
+	referenceChainID := c.chainID
+	if referenceChainID == nil {
+		counts := make(map[string]int)
+		for _, r := range results {
+			if r.err != nil {
+				continue
+			}
+
+			counts[string(r.chainID)]++
+			if referenceChainID == nil || counts[string(r.chainID)] > counts[string(referenceChainID)] {
+				referenceChainID = r.chainID
+			}
+		}
+	}
+
+	var maxHeight uint64
+	for _, r := range results {
+		if r.err == nil && bytes.Equal(r.chainID, referenceChainID) && r.height > maxHeight {
+			maxHeight = r.height
+		}
+	}
+
+	now := time.Now()
+	for i, endpoint := range c.endpoints {
+		r := results[i]
+		endpoint.LastChecked = now
+		endpoint.LastError = r.err
+		endpoint.HeadHeight = r.height
+		endpoint.ChainID = r.chainID
+
+		switch {
+		case r.err != nil:
+		case !bytes.Equal(r.chainID, referenceChainID):
+			endpoint.LastError = fmt.Errorf("%w: expected %s, got %s", ErrChainIDMismatch, base64.URLEncoding.EncodeToString(referenceChainID), base64.URLEncoding.EncodeToString(r.chainID))
+		case maxHeight-r.height > MaxHeadHeightLag:
+			endpoint.LastError = fmt.Errorf("%w: head block %d is %d blocks behind", ErrEndpointBehind, r.height, maxHeight-r.height)
+		}
+
+		endpoint.Healthy = endpoint.LastError == nil
+	}
+
+	c.lastCheck = now
+
+	// Move off of the current endpoint if it is no longer healthy
+	if !c.endpoints[c.current].Healthy {
+		for i, endpoint := range c.endpoints {
+			if endpoint.Healthy {
+				c.current = i
+				break
+			}
+		}
+	}
+
+	endpoints := make([]RPCEndpoint, len(c.endpoints))
+	for i, endpoint := range c.endpoints {
+		endpoints[i] = *endpoint
+	}
+
+	return endpoints
+}
+
+// candidates returns the endpoints in the order calls should try them: the current endpoint, the other healthy
+// endpoints, then the unhealthy endpoints as a last resort
+func (c *KoinosRPCClient) candidates() []*RPCEndpoint {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	healthy := make([]*RPCEndpoint, 0, len(c.endpoints))
+	unhealthy := make([]*RPCEndpoint, 0)
+	for i := range c.endpoints {
+		endpoint := c.endpoints[(c.current+i)%len(c.endpoints)]
+		if endpoint.Healthy {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// startHealthCheck checks the endpoints in the background when there are several endpoints to choose from and they
+// have not been checked recently, so that calls are not held up by the check. The check is not traced, as the user did
+// not make it
+func (c *KoinosRPCClient) startHealthCheck() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.cancelCheck != nil || len(c.endpoints) < 2 || time.Since(c.lastCheck) <= HealthCheckInterval {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(WithoutTrace(context.Background()))
+	c.cancelCheck = cancel
+	c.checks.Add(1)
+
+	go func() {
+		defer c.checks.Done()
+
+		c.CheckHealth(ctx)
+
+		c.mutex.Lock()
+		c.cancelCheck = nil
+		c.mutex.Unlock()
+		cancel()
+	}()
+}
+
+// markFailed records a transport error on an endpoint and moves calls to the next endpoint
+func (c *KoinosRPCClient) markFailed(endpoint *RPCEndpoint, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	endpoint.Healthy = false
+	endpoint.LastError = err
+
+	if c.endpoints[c.current] == endpoint {
+		c.current = (c.current + 1) % len(c.endpoints)
+	}
+}
+
+// markSucceeded routes further calls to an endpoint that answered. Endpoints that failed their health check
+// stay unhealthy until the next check
+func (c *KoinosRPCClient) markSucceeded(endpoint *RPCEndpoint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if errors.Is(endpoint.LastError, ErrChainIDMismatch) || errors.Is(endpoint.LastError, ErrEndpointBehind) {
+		return
+	}
+
+	endpoint.Healthy = true
+	endpoint.LastError = nil
+
+	for i, e := range c.endpoints {
+		if e == endpoint {
+			c.current = i
+			break
+		}
+	}
+}
+
+// Call wraps the rpc client call and handles some of the boilerplate. Calls fail over to the next endpoint on
+// transport errors, except for transaction submissions, which may have been accepted even though the call failed.
+// Idempotent reads are retried with an exponential backoff when no endpoint could be reached
 func (c *KoinosRPCClient) Call(ctx context.Context, method string, params proto.Message, returnType proto.Message) error {
+	if len(c.endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
 	req, err := kjson.Marshal(params)
 	if err != nil {
 		return err
 	}
 
+	c.startHealthCheck()
+
+	options := c.Options()
+	delay := options.RetryDelay
+	for attempt := 0; ; attempt++ {
+		unreachable, err := c.callWithFailover(ctx, method, params, req, returnType)
+		if err == nil || !unreachable || !idempotentCalls[method] || attempt >= options.Retries {
+			return err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+
+		delay *= 2
+	}
+}
+
+// callWithFailover tries the call on each endpoint in turn until one responds. It returns true when the error
+// is because no endpoint could be reached
+func (c *KoinosRPCClient) callWithFailover(ctx context.Context, method string, params proto.Message, req json.RawMessage, returnType proto.Message) (bool, error) {
+	var lastErr error
+	for _, endpoint := range c.candidates() {
+		err := c.callEndpoint(ctx, endpoint, method, params, req, returnType)
+		if err == nil {
+			c.markSucceeded(endpoint)
+			return false, nil
+		}
+
+		// Errors returned by the node itself and cancelled calls are not retried
+		var rpcErr KoinosRPCError
+		if errors.As(err, &rpcErr) || ctx.Err() != nil {
+			return false, err
+		}
+
+		// A response that cannot be decoded still means the node is reachable
+		if errors.Is(err, ErrInvalidResponse) {
+			return false, err
+		}
+
+		// A request that cannot be made says nothing about the endpoint, and would fail on the others too
+		if errors.Is(err, ErrInvalidParam) {
+			return false, err
+		}
+
+		c.markFailed(endpoint, err)
+
+		if method == SubmitTransactionCall {
+			return false, fmt.Errorf("%w: %s did not respond, the transaction may still have been accepted. Check with tx_info before resubmitting: %v", ErrSubmissionUncertain, endpoint.URL, err)
+		}
+
+		lastErr = err
+	}
+
+	return true, lastErr
+}
+
+// callEndpoint makes an rpc call to a single endpoint, giving up after the call timeout
+func (c *KoinosRPCClient) callEndpoint(ctx context.Context, endpoint *RPCEndpoint, method string, params proto.Message, req json.RawMessage, returnType proto.Message) error {
+	options := c.Options()
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := endpoint.client.Call(ctx, method, params, req, returnType)
+
+	if options.Tracer != nil && !isUntraced(ctx) {
+		options.Tracer.Trace(endpoint.URL, method, params, returnType, time.Since(start), err)
+	}
+
+	return err
+}
+
+// doCall makes the json rpc call and decodes the response
+func doCall(ctx context.Context, client jsonrpc.RPCClient, method string, req json.RawMessage, returnType proto.Message) error {
 	// Make the rpc call
-	resp, err := c.client.Call(ctx, method, json.RawMessage(req))
+	resp, err := client.Call(ctx, method, req)
 	if err != nil {
 		return err
 	}
-	if resp.Error != nil {
-		err := KoinosRPCError{message: resp.Error.Message}
-
-		if data, ok := resp.Error.Data.(string); ok {
-			dataMap := make(map[string][]string)
-			e := json.Unmarshal([]byte(data), &dataMap)
-			if e == nil {
-				if logs, ok := dataMap["logs"]; ok {
-					err.Logs = logs
-				}
-			}
-		}
 
-		return err
+	return decodeResponse(resp, returnType)
+}
+
+// decodeResponse decodes a json rpc response into the return type
+func decodeResponse(resp *jsonrpc.RPCResponse, returnType proto.Message) error {
+	if resp.Error != nil {
+		return NewKoinosRPCError(resp.Error.Message, resp.Error.Data)
 	}
 
 	// Fetch the contract response
 	raw := json.RawMessage{}
 
-	err = resp.GetObject(&raw)
+	err := resp.GetObject(&raw)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
 	}
 
 	err = kjson.Unmarshal([]byte(raw), returnType)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
 	}
 
 	return nil
 }
 
+// RPCMessageTypes returns the request and response types of a known rpc method, such as chain.get_head_info
+func RPCMessageTypes(method string) (protoreflect.MessageType, protoreflect.MessageType, bool) {
+	parts := strings.Split(method, ".")
+	if len(parts) != 2 {
+		return nil, nil, false
+	}
+
+	prefix := fmt.Sprintf("koinos.rpc.%s.%s", parts[0], parts[1])
+	requestType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(prefix + "_request"))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	responseType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(prefix + "_response"))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return requestType, responseType, true
+}
+
+// CallRaw makes an rpc call with json params. When the method is known, the params are validated against its
+// request type and the response is decoded into its response type. Otherwise the json is passed through as is
+func (c *KoinosRPCClient) CallRaw(ctx context.Context, method string, params string) (proto.Message, error) {
+	var request, response proto.Message
+	if requestType, responseType, ok := RPCMessageTypes(method); ok {
+		request = requestType.New().Interface()
+		response = responseType.New().Interface()
+	} else {
+		request = &structpb.Value{}
+		response = &structpb.Value{}
+	}
+
+	// Unknown fields are rejected so that typos in the params are not silently dropped
+	options := kjson.KoinosUnmarshalOptions
+	options.DiscardUnknown = false
+	if err := options.Unmarshal([]byte(params), request); err != nil {
+		return nil, fmt.Errorf("%w: params do not match %s, %v", ErrInvalidParam, request.ProtoReflect().Descriptor().FullName(), err)
+	}
+
+	if err := c.Call(ctx, method, request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // GetAccountBalance gets the balance of a given account
 func (c *KoinosRPCClient) GetAccountBalance(ctx context.Context, address []byte, contractID []byte, balanceOfEntry uint32) (uint64, error) {
 	// Make the rpc call
@@ -307,3 +870,147 @@ func (c *KoinosRPCClient) GetPendingNonce(ctx context.Context, address []byte) (
 
 	return nonce, nil
 }
+
+// GetPendingTransactions gets up to limit transactions from the mempool, oldest first
+func (c *KoinosRPCClient) GetPendingTransactions(ctx context.Context, limit uint64) ([]*mempool.PendingTransaction, error) {
+	// Build the request
+	params := mempool.GetPendingTransactionsRequest{
+		Limit: limit,
+	}
+
+	// Make the rpc call
+	var mResp mempool.GetPendingTransactionsResponse
+	err := c.Call(ctx, GetPendingTransactionsCall, &params, &mResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return mResp.PendingTransactions, nil
+}
+
+// GetResourceLimits gets the current resource limits and costs of the chain
+func (c *KoinosRPCClient) GetResourceLimits(ctx context.Context) (*koinos_chain.ResourceLimitData, error) {
+	// Build the request
+	params := chain.GetResourceLimitsRequest{}
+
+	// Make the rpc call
+	var cResp chain.GetResourceLimitsResponse
+	err := c.Call(ctx, GetResourceLimitsCall, &params, &cResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if cResp.ResourceLimitData == nil {
+		return nil, fmt.Errorf("%w: no resource limits", ErrInvalidResponse)
+	}
+
+	return cResp.ResourceLimitData, nil
+}
+
+// GetHeadInfo gets the head info of the chain
+func (c *KoinosRPCClient) GetHeadInfo(ctx context.Context) (*chain.GetHeadInfoResponse, error) {
+	// Build the request
+	params := chain.GetHeadInfoRequest{}
+
+	// Make the rpc call
+	var cResp chain.GetHeadInfoResponse
+	err := c.Call(ctx, GetHeadInfoCall, &params, &cResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cResp, nil
+}
+
+// GetForkHeads gets the fork heads and last irreversible block of the chain
+func (c *KoinosRPCClient) GetForkHeads(ctx context.Context) (*chain.GetForkHeadsResponse, error) {
+	// Build the request
+	params := chain.GetForkHeadsRequest{}
+
+	// Make the rpc call
+	var cResp chain.GetForkHeadsResponse
+	err := c.Call(ctx, GetForkHeadsCall, &params, &cResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cResp, nil
+}
+
+// GetBlocksByID gets blocks from the block store by their ids
+func (c *KoinosRPCClient) GetBlocksByID(ctx context.Context, blockIDs [][]byte, returnReceipt bool) ([]*block_store.BlockItem, error) {
+	// Build the request
+	params := block_store.GetBlocksByIdRequest{
+		BlockIds:      blockIDs,
+		ReturnBlock:   true,
+		ReturnReceipt: returnReceipt,
+	}
+
+	// Make the rpc call
+	var bResp block_store.GetBlocksByIdResponse
+	err := c.Call(ctx, GetBlocksByIDCall, &params, &bResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return bResp.BlockItems, nil
+}
+
+// GetBlocksByHeight gets a range of blocks from the block store, starting at the given height on the fork of the given head block
+func (c *KoinosRPCClient) GetBlocksByHeight(ctx context.Context, headBlockID []byte, startHeight uint64, numBlocks uint32, returnReceipt bool) ([]*block_store.BlockItem, error) {
+	// Build the request
+	params := block_store.GetBlocksByHeightRequest{
+		HeadBlockId:         headBlockID,
+		AncestorStartHeight: startHeight,
+		NumBlocks:           numBlocks,
+		ReturnBlock:         true,
+		ReturnReceipt:       returnReceipt,
+	}
+
+	// Make the rpc call
+	var bResp block_store.GetBlocksByHeightResponse
+	err := c.Call(ctx, GetBlocksByHeightCall, &params, &bResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return bResp.BlockItems, nil
+}
+
+// GetTransactionsByID gets transactions and the ids of their containing blocks from the transaction store
+func (c *KoinosRPCClient) GetTransactionsByID(ctx context.Context, transactionIDs [][]byte) ([]*transaction_store.TransactionItem, error) {
+	// Build the request
+	params := transaction_store_rpc.GetTransactionsByIdRequest{
+		TransactionIds: transactionIDs,
+	}
+
+	// Make the rpc call
+	var tResp transaction_store_rpc.GetTransactionsByIdResponse
+	err := c.Call(ctx, GetTransactionsByIDCall, &params, &tResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return tResp.Transactions, nil
+}
+
+// GetAccountHistory gets the transactions and blocks affecting an account, newest first unless ascending. The history
+// starts at the given sequence number, or at the newest (or oldest) entry if it is nil
+func (c *KoinosRPCClient) GetAccountHistory(ctx context.Context, address []byte, seqNum *uint64, limit uint64, ascending bool) ([]*account_history_rpc.AccountHistoryEntry, error) {
+	// Build the request
+	params := account_history_rpc.GetAccountHistoryRequest{
+		Address:   address,
+		SeqNum:    seqNum,
+		Limit:     limit,
+		Ascending: ascending,
+	}
+
+	// Make the rpc call
+	var hResp account_history_rpc.GetAccountHistoryResponse
+	err := c.Call(ctx, GetAccountHistoryCall, &params, &hResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return hResp.Values, nil
+}
@@ -0,0 +1,197 @@
+package cliutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RedactedValue replaces signatures and secrets in the rpc trace
+const RedactedValue = "[redacted]"
+
+// AddressLength is the length in bytes of a koinos address
+const AddressLength = 25
+
+// RPCTracer logs every JSON-RPC request and response made by the rpc client
+type RPCTracer struct {
+	// DecodeBytes shows base64 fields as hex, or as base58 when they look like an address
+	DecodeBytes bool
+
+	writer io.Writer
+	file   *os.File
+	mutex  sync.Mutex
+}
+
+// NewRPCTracer creates a tracer that writes to the given writer
+func NewRPCTracer(writer io.Writer, decodeBytes bool) *RPCTracer {
+	return &RPCTracer{DecodeBytes: decodeBytes, writer: writer}
+}
+
+// NewRPCFileTracer creates a tracer that appends to the given file
+func NewRPCFileTracer(filename string, decodeBytes bool) (*RPCTracer, error) {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCTracer{DecodeBytes: decodeBytes, writer: file, file: file}, nil
+}
+
+// Filename returns the name of the file the tracer writes to, or an empty string if it does not write to a file
+func (t *RPCTracer) Filename() string {
+	if t.file == nil {
+		return ""
+	}
+
+	return t.file.Name()
+}
+
+// Close closes the trace file, if there is one
+func (t *RPCTracer) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+
+	return t.file.Close()
+}
+
+// untracedKey marks a context whose rpc calls are left out of the trace
+type untracedKey struct{}
+
+// WithoutTrace returns a context whose rpc calls are not traced, for calls the user did not make, like the heartbeat
+func WithoutTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untracedKey{}, true)
+}
+
+// isUntraced returns true if the context's rpc calls are left out of the trace
+func isUntraced(ctx context.Context) bool {
+	untraced, _ := ctx.Value(untracedKey{}).(bool)
+	return untraced
+}
+
+// Trace logs a single rpc call. The response is only logged when the call succeeded
+func (t *RPCTracer) Trace(url string, method string, params proto.Message, response proto.Message, latency time.Duration, err error) {
+	s := fmt.Sprintf("[%s] %s on %s (%s)\n", time.Now().UTC().Format(time.RFC3339), method, url, latency.Round(time.Microsecond))
+	s += "  params: " + t.messageToString(params) + "\n"
+
+	if err == nil {
+		s += "  result: " + t.messageToString(response) + "\n"
+	} else {
+		s += "  error: " + err.Error() + "\n"
+
+		var rpcErr KoinosRPCError
+		if errors.As(err, &rpcErr) {
+			if rpcErr.Code != 0 {
+				s += fmt.Sprintf("  code: %s (%d)\n", rpcErr.Code, rpcErr.Code)
+			}
+
+			for _, log := range rpcErr.Logs {
+				s += "  log: " + log + "\n"
+			}
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	fmt.Fprint(t.writer, s)
+}
+
+func (t *RPCTracer) messageToString(message proto.Message) string {
+	if message == nil {
+		return "{}"
+	}
+
+	options := kjson.KoinosMarshalOptions
+	options.FieldOverrides = readableFieldOverrides(true, t.DecodeBytes)
+
+	b, err := options.Marshal(message)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+// ReadableJSON marshals a message to indented json, showing plain base64 bytes fields as hex, or as base58 when they look like an address
+func ReadableJSON(message proto.Message) (string, error) {
+	options := kjson.KoinosMarshalOptions
+	options.FieldOverrides = readableFieldOverrides(false, true)
+
+	b, err := options.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := gojson.Indent(buffer, b, "", "  "); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// readableFieldOverrides builds the json overrides for bytes fields, optionally redacting signatures and secrets
+// and decoding plain base64 fields
+func readableFieldOverrides(redact bool, decodeBytes bool) map[protoreflect.Kind]protojson.FieldOverride {
+	koinosOverride := kjson.KoinosFieldOverrides[protoreflect.BytesKind]
+
+	return map[protoreflect.Kind]protojson.FieldOverride{
+		protoreflect.BytesKind: {
+			MarshalField: func(val protoreflect.Value, fd protoreflect.FieldDescriptor, opt protojson.MarshalOptions) ([]byte, error) {
+				if redact && isSecretField(fd) {
+					return gojson.Marshal(RedactedValue)
+				}
+
+				b, err := koinosOverride.MarshalField(val, fd, opt)
+				if err != nil || !decodeBytes {
+					return b, err
+				}
+
+				// Only plain base64 fields are decoded, fields with a koinos bytes type are already readable
+				var encoded string
+				if err := gojson.Unmarshal(b, &encoded); err != nil || encoded != base64.URLEncoding.EncodeToString(val.Bytes()) {
+					return b, err
+				}
+
+				data := val.Bytes()
+				if len(data) == AddressLength && data[0] == 0 {
+					return gojson.Marshal(base58.Encode(data))
+				}
+
+				return gojson.Marshal("0x" + hex.EncodeToString(data))
+			},
+			UnmarshalField: koinosOverride.UnmarshalField,
+		},
+	}
+}
+
+// isSecretField returns true for fields that should never be written to the trace
+func isSecretField(fd protoreflect.FieldDescriptor) bool {
+	name := strings.ToLower(string(fd.Name()))
+	for _, secret := range []string{"signature", "private", "secret", "password", "passphrase", "wif"} {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,12 +4,28 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	kjson "github.com/koinos/koinos-proto-golang/v2/encoding/json"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/canonical"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	util "github.com/koinos/koinos-util-golang/v2"
 	"github.com/multiformats/go-multihash"
+	"google.golang.org/protobuf/proto"
+)
+
+// Transaction encoding formats
+const (
+	TransactionFormatJSON   = "json"
+	TransactionFormatBase64 = "base64"
+	TransactionFormatBoth   = "both"
 )
 
 // CreateSignedTransaction creates a signed transaction
@@ -106,3 +122,111 @@ func SignTransaction(key []byte, tx *protocol.Transaction) error {
 
 	return nil
 }
+
+// DecodeTransaction decodes a transaction given as JSON or base64 protobuf, either inline or from a file path
+func DecodeTransaction(input string) (*protocol.Transaction, error) {
+	data := strings.TrimSpace(input)
+
+	// If the input names an existing file, decode its contents instead
+	if info, err := os.Stat(data); err == nil && !info.IsDir() {
+		fileData, err := os.ReadFile(data)
+		if err != nil {
+			return nil, err
+		}
+
+		data = strings.TrimSpace(string(fileData))
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty transaction", ErrInvalidTransaction)
+	}
+
+	transaction := &protocol.Transaction{}
+
+	// JSON transactions always begin with an object
+	if data[0] == '{' {
+		err := kjson.Unmarshal([]byte(data), transaction)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTransaction, err)
+		}
+
+		return transaction, nil
+	}
+
+	txBytes, err := DecodeBase64(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTransaction, err)
+	}
+
+	err = proto.Unmarshal(txBytes, transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTransaction, err)
+	}
+
+	return transaction, nil
+}
+
+// DecodeBase64 decodes URL-safe or standard base64, with or without padding
+func DecodeBase64(data string) ([]byte, error) {
+	data = strings.TrimRight(strings.TrimSpace(data), "=")
+
+	if strings.ContainsAny(data, "+/") {
+		return base64.RawStdEncoding.DecodeString(data)
+	}
+
+	return base64.RawURLEncoding.DecodeString(data)
+}
+
+// EncodeTransaction encodes a transaction as either indented JSON or URL-safe base64 protobuf
+func EncodeTransaction(transaction *protocol.Transaction, format string) (string, error) {
+	switch format {
+	case TransactionFormatJSON:
+		unformattedJSON, err := kjson.Marshal(transaction)
+		if err != nil {
+			return "", err
+		}
+
+		buffer := bytes.NewBuffer(make([]byte, 0))
+		err = json.Indent(buffer, unformattedJSON, "", "  ")
+		if err != nil {
+			return "", err
+		}
+
+		return buffer.String(), nil
+
+	case TransactionFormatBase64:
+		data, err := proto.Marshal(transaction)
+		if err != nil {
+			return "", err
+		}
+
+		return base64.URLEncoding.EncodeToString(data), nil
+	}
+
+	return "", fmt.Errorf("%w: unknown transaction format %s", ErrInvalidParam, format)
+}
+
+// TransactionSigners returns the addresses of the signers of a transaction, recovered from its signatures
+func TransactionSigners(tx *protocol.Transaction) ([]string, error) {
+	idBytes, err := multihash.Decode(tx.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := make([]string, 0, len(tx.Signatures))
+	for _, signature := range tx.Signatures {
+		publicKey, _, err := btcec.RecoverCompact(btcec.S256(), signature, idBytes.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := btcutil.NewAddressPubKey(publicKey.SerializeCompressed(), &chaincfg.MainNetParams)
+		if err != nil {
+			return nil, err
+		}
+
+		signers = append(signers, address.EncodeAddress())
+	}
+
+	return signers, nil
+}
@@ -0,0 +1,183 @@
+package cliutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	jsonrpc "github.com/ybbus/jsonrpc/v3"
+)
+
+// UnixScheme is the url scheme of endpoints reached through a unix domain socket, e.g. unix:///var/run/koinos.sock
+const UnixScheme = "unix://"
+
+// EndpointTLSConfig is the client certificate and certificate authority used to reach an endpoint
+type EndpointTLSConfig struct {
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	CA         string `json:"ca,omitempty"`
+}
+
+// EndpointConfig is the transport configuration of a single rpc endpoint. Header values may reference
+// environment variables, e.g. "Bearer ${KOINOS_API_KEY}"
+type EndpointConfig struct {
+	URL     string             `json:"url"`
+	Headers map[string]string  `json:"headers,omitempty"`
+	Proxy   string             `json:"proxy,omitempty"`
+	TLS     *EndpointTLSConfig `json:"tls,omitempty"`
+}
+
+// RPCConfig is the contents of an rpc config file
+type RPCConfig struct {
+	Endpoints []EndpointConfig `json:"endpoints"`
+}
+
+// LoadRPCConfig reads an rpc config file
+func LoadRPCConfig(filename string) (*RPCConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &RPCConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%w: %s, %v", ErrInvalidParam, filename, err)
+	}
+
+	for _, endpoint := range config.Endpoints {
+		if endpoint.URL == "" {
+			return nil, fmt.Errorf("%w: %s has an endpoint without a url", ErrInvalidParam, filename)
+		}
+	}
+
+	return config, nil
+}
+
+// EndpointConfigs returns the endpoint configs for a connect argument, which is either an rpc config file
+// ending in .json or a comma separated list of urls
+func EndpointConfigs(arg string) ([]EndpointConfig, error) {
+	if strings.HasSuffix(arg, ".json") {
+		if _, err := os.Stat(arg); err == nil {
+			config, err := LoadRPCConfig(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			return config.Endpoints, nil
+		}
+	}
+
+	urls := ParseEndpoints(arg)
+	configs := make([]EndpointConfig, len(urls))
+	for i, url := range urls {
+		configs[i] = EndpointConfig{URL: url}
+	}
+
+	return configs, nil
+}
+
+// ParseHeader parses a header given as "Name: value"
+func ParseHeader(header string) (string, string, error) {
+	parts := strings.SplitN(header, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return "", "", fmt.Errorf("%w: header must be given as 'Name: value'", ErrInvalidParam)
+	}
+
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+// newEndpointClient creates the transport for an endpoint. A config with only a url cannot fail
+func newEndpointClient(config EndpointConfig) (rpcTransport, error) {
+	if isAMQPURL(config.URL) {
+		return newAMQPTransport(config)
+	}
+
+	if len(config.Headers) == 0 && config.Proxy == "" && config.TLS == nil && !strings.HasPrefix(config.URL, UnixScheme) {
+		return &jsonRPCTransport{client: jsonrpc.NewClient(config.URL)}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	endpoint := config.URL
+
+	if strings.HasPrefix(config.URL, UnixScheme) {
+		socket := strings.TrimPrefix(config.URL, UnixScheme)
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+
+		// The host is ignored, every request goes to the socket
+		endpoint = "http://unix/"
+	}
+
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid proxy %s, %v", ErrInvalidParam, config.Proxy, err)
+		}
+
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("%w: proxy scheme must be one of (http, https, socks5)", ErrInvalidParam)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	headers := make(map[string]string, len(config.Headers))
+	for name, value := range config.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+
+	client := jsonrpc.NewClientWithOpts(endpoint, &jsonrpc.RPCClientOpts{
+		HTTPClient:    &http.Client{Transport: transport},
+		CustomHeaders: headers,
+	})
+
+	return &jsonRPCTransport{client: client}, nil
+}
+
+// load builds the tls config from the certificate files
+func (c *EndpointTLSConfig) load() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not load client certificate, %v", ErrInvalidParam, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CA != "" {
+		pem, err := os.ReadFile(c.CA)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not read CA bundle, %v", ErrInvalidParam, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates found in CA bundle %s", ErrInvalidParam, c.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
@@ -6,7 +6,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/btcsuite/btcutil/base58"
+	koinos_chain "github.com/koinos/koinos-proto-golang/v2/koinos/chain"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	util "github.com/koinos/koinos-util-golang/v2"
 	"github.com/minio/sio"
@@ -35,8 +39,25 @@ const (
 	RIPEMD320 = 0x1055
 )
 
-// TransactionReceiptToString creates a string from a receipt
-func TransactionReceiptToString(receipt *protocol.TransactionReceipt, operations int) string {
+// EventDecoder resolves contract names and decodes event data for display
+type EventDecoder interface {
+	// ContractName returns the registered name of the contract at the given address
+	ContractName(address []byte) (string, bool)
+
+	// DecodeEvent returns a human readable representation of the event data
+	DecodeEvent(event *protocol.EventData) (string, error)
+}
+
+// OperationDecoder decodes contract calls and events for display
+type OperationDecoder interface {
+	EventDecoder
+
+	// DecodeCall returns the method name and a human readable representation of the arguments of a contract call
+	DecodeCall(contractID []byte, entryPoint uint32, args []byte) (string, string, error)
+}
+
+// TransactionReceiptToString creates a string from a receipt, decoding events with the given decoder if it is not nil
+func TransactionReceiptToString(receipt *protocol.TransactionReceipt, operations int, decoder EventDecoder) string {
 	s := fmt.Sprintf("Transaction with ID 0x%s containing %d operations", hex.EncodeToString(receipt.Id), operations)
 	if receipt.Reverted {
 		s += " reverted."
@@ -61,9 +82,173 @@ func TransactionReceiptToString(receipt *protocol.TransactionReceipt, operations
 		}
 	}
 
+	// Show events if available
+	if len(receipt.Events) > 0 {
+		s += "\nEvents:"
+		for _, event := range receipt.Events {
+			s += "\n" + EventToString(event, decoder)
+		}
+	}
+
+	return s
+}
+
+// EventToString creates a string from an event, falling back to hex when the event data cannot be decoded
+func EventToString(event *protocol.EventData, decoder EventDecoder) string {
+	source := base58.Encode(event.Source)
+	if decoder != nil {
+		if name, ok := decoder.ContractName(event.Source); ok {
+			source = fmt.Sprintf("%s (%s)", name, source)
+		}
+	}
+
+	s := fmt.Sprintf("  [%d] %s from %s", event.Sequence, event.Name, source)
+
+	if len(event.Impacted) > 0 {
+		impacted := make([]string, len(event.Impacted))
+		for i, address := range event.Impacted {
+			impacted[i] = base58.Encode(address)
+		}
+
+		s += "\n      Impacted: " + strings.Join(impacted, ", ")
+	}
+
+	data := ""
+	if decoder != nil {
+		decoded, err := decoder.DecodeEvent(event)
+		if err == nil {
+			data = decoded
+		}
+	}
+
+	if len(data) == 0 {
+		data = "0x" + hex.EncodeToString(event.Data)
+	}
+
+	s += "\n      Data: " + data
+
+	return s
+}
+
+// OperationToString creates a string from an operation, decoding contract calls with the given decoder if it is not nil
+func OperationToString(op *protocol.Operation, decoder OperationDecoder) string {
+	switch o := op.Op.(type) {
+	case *protocol.Operation_UploadContract:
+		return fmt.Sprintf("Upload contract %s (%d bytes)", base58.Encode(o.UploadContract.ContractId), len(o.UploadContract.Bytecode))
+
+	case *protocol.Operation_CallContract:
+		call := o.CallContract
+		contract := base58.Encode(call.ContractId)
+		if decoder != nil {
+			if name, ok := decoder.ContractName(call.ContractId); ok {
+				contract = fmt.Sprintf("%s (%s)", name, contract)
+			}
+
+			method, args, err := decoder.DecodeCall(call.ContractId, call.EntryPoint, call.Args)
+			if err == nil {
+				return fmt.Sprintf("Call %s on contract %s with arguments %s", method, contract, args)
+			}
+		}
+
+		return fmt.Sprintf("Call contract %s at entry point 0x%08x with arguments 0x%s", contract, call.EntryPoint, hex.EncodeToString(call.Args))
+
+	case *protocol.Operation_SetSystemCall:
+		callID := koinos_chain.SystemCallId(o.SetSystemCall.CallId).String()
+		switch target := o.SetSystemCall.GetTarget().GetTarget().(type) {
+		case *protocol.SystemCallTarget_ThunkId:
+			return fmt.Sprintf("Set system call %s to thunk %d", callID, target.ThunkId)
+		case *protocol.SystemCallTarget_SystemCallBundle:
+			return fmt.Sprintf("Set system call %s to contract %s at entry point 0x%08x", callID, base58.Encode(target.SystemCallBundle.ContractId), target.SystemCallBundle.EntryPoint)
+		}
+
+		return fmt.Sprintf("Set system call %s", callID)
+
+	case *protocol.Operation_SetSystemContract:
+		if o.SetSystemContract.SystemContract {
+			return fmt.Sprintf("Set contract %s to system level permissions", base58.Encode(o.SetSystemContract.ContractId))
+		}
+
+		return fmt.Sprintf("Set contract %s to user level permissions", base58.Encode(o.SetSystemContract.ContractId))
+	}
+
+	return "Unknown operation"
+}
+
+// TransactionToString creates a string from a transaction, showing its signers, payer and each operation
+func TransactionToString(transaction *protocol.Transaction, decoder OperationDecoder) string {
+	s := fmt.Sprintf("Transaction 0x%s", hex.EncodeToString(transaction.Id))
+
+	header := transaction.GetHeader()
+	s += fmt.Sprintf("\n  Payer: %s", base58.Encode(header.GetPayer()))
+	if len(header.GetPayee()) > 0 {
+		s += fmt.Sprintf("\n  Payee: %s", base58.Encode(header.GetPayee()))
+	}
+
+	signers, err := TransactionSigners(transaction)
+	if err != nil {
+		s += "\n  Signers: " + err.Error()
+	} else {
+		s += "\n  Signers: " + strings.Join(signers, ", ")
+	}
+
+	nonce, err := util.NonceBytesToUInt64(header.GetNonce())
+	if err == nil {
+		s += fmt.Sprintf("\n  Nonce: %d", nonce)
+	}
+
+	rcLimit, err := util.SatoshiToDecimal(header.GetRcLimit(), KoinPrecision)
+	if err == nil {
+		s += fmt.Sprintf("\n  RC limit: %v %s", rcLimit, ManaSymbol)
+	}
+
+	s += fmt.Sprintf("\n  Operations (%d):", len(transaction.Operations))
+	for i, op := range transaction.Operations {
+		s += fmt.Sprintf("\n    %d: %s", i, OperationToString(op, decoder))
+	}
+
+	return s
+}
+
+// BlockToString creates a string from a block and its optional receipt. If showOps is true, every transaction is shown
+func BlockToString(block *protocol.Block, receipt *protocol.BlockReceipt, showOps bool, decoder OperationDecoder) string {
+	header := block.GetHeader()
+
+	s := fmt.Sprintf("Block 0x%s at height %d", hex.EncodeToString(block.Id), header.GetHeight())
+	s += fmt.Sprintf("\n  Previous: 0x%s", hex.EncodeToString(header.GetPrevious()))
+	s += fmt.Sprintf("\n  Timestamp: %s", TimestampToString(header.GetTimestamp()))
+	s += fmt.Sprintf("\n  Signer: %s", base58.Encode(header.GetSigner()))
+	s += fmt.Sprintf("\n  Transactions: %d", len(block.Transactions))
+
+	if receipt != nil {
+		s += fmt.Sprintf("\n  Resources used (Disk: %d, Network: %d, Compute: %d)", receipt.DiskStorageUsed, receipt.NetworkBandwidthUsed, receipt.ComputeBandwidthUsed)
+	}
+
+	if showOps {
+		for _, transaction := range block.Transactions {
+			s += "\n  " + strings.ReplaceAll(TransactionToString(transaction, decoder), "\n", "\n  ")
+		}
+	}
+
 	return s
 }
 
+// BlockSummaryString creates a single line summary of a block
+func BlockSummaryString(block *protocol.Block) string {
+	header := block.GetHeader()
+	return fmt.Sprintf("Height: %d ID: 0x%s Time: %s Transactions: %d Signer: %s", header.GetHeight(), hex.EncodeToString(block.Id),
+		TimestampToString(header.GetTimestamp()), len(block.Transactions), base58.Encode(header.GetSigner()))
+}
+
+// TimestampToString creates a string from a koinos millisecond timestamp
+func TimestampToString(timestamp uint64) string {
+	return TimestampToTime(timestamp).Format(time.RFC3339)
+}
+
+// TimestampToTime converts a koinos millisecond timestamp to a UTC time
+func TimestampToTime(timestamp uint64) time.Time {
+	return time.Unix(0, int64(timestamp)*int64(time.Millisecond)).UTC()
+}
+
 func walletConfig(password []byte) sio.Config {
 	return sio.Config{
 		MinVersion:     sio.Version20,